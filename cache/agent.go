@@ -1,18 +1,24 @@
 package cache
 
 import (
-	"bytes"
+	"context"
 	"github.com/behavioral-ai/collective/operations"
 	"github.com/behavioral-ai/collective/repository"
 	"github.com/behavioral-ai/core/access2"
 	"github.com/behavioral-ai/core/httpx"
 	"github.com/behavioral-ai/core/messaging"
 	"github.com/behavioral-ai/core/rest"
-	"github.com/behavioral-ai/core/uri"
-	"github.com/behavioral-ai/intermediary/cache/representation1"
+	"github.com/behavioral-ai/intermediary/cache/representation2"
+	"github.com/behavioral-ai/intermediary/compression"
+	"github.com/behavioral-ai/intermediary/config"
+	"github.com/behavioral-ai/intermediary/latency"
+	"github.com/behavioral-ai/intermediary/namespace"
+	"github.com/behavioral-ai/intermediary/probation"
+	"github.com/behavioral-ai/intermediary/representation"
 	"github.com/behavioral-ai/intermediary/request"
 	"io"
 	"net/http"
+	"sync/atomic"
 	"time"
 )
 
@@ -27,38 +33,65 @@ var (
 )
 
 type agentT struct {
-	state    *representation1.Cache
+	name     string
+	state    atomic.Pointer[representation2.Cache]
+	running  atomic.Bool
 	exchange rest.Exchange
 	service  *operations.Service
+	latency  *latency.Tracker
 
-	review   *messaging.Review
-	ticker   *messaging.Ticker
-	emissary *messaging.Channel
+	review     *messaging.Review
+	controller *representation.Controller
+	ticker     *messaging.Ticker
+	emissary   *messaging.Channel
 }
 
 // init - register an agent constructor
 func init() {
-	repository.RegisterConstructor(NamespaceName, func() messaging.Agent {
-		return newAgent(representation1.Initialize(nil), nil, operations.Serve)
-	})
+	repository.RegisterConstructor(NamespaceName, Constructor)
+}
+
+// Constructor - create an agent with the default representation
+func Constructor() messaging.Agent {
+	return newAgent(representation2.Initialize(nil), nil, operations.Serve)
+}
+
+// NewInstance - create a named instance with the default representation
+func NewInstance(instance string) messaging.Agent {
+	a := newAgent(representation2.Initialize(nil), nil, operations.Serve)
+	a.name = namespace.Instance(NamespaceName, instance)
+	return a
 }
 
 func ConstructorOverride(m map[string]string, ex rest.Exchange, service *operations.Service) {
 	repository.RegisterConstructor(NamespaceName, func() messaging.Agent {
-		return newAgent(representation1.Initialize(m), ex, service)
+		return newAgent(representation2.Initialize(m), ex, service)
+	})
+}
+
+// InstanceOverride - register a constructor for a named instance, each instance has its own representation
+func InstanceOverride(instance string, m map[string]string, ex rest.Exchange, service *operations.Service) {
+	name := namespace.Instance(NamespaceName, instance)
+	repository.RegisterConstructor(name, func() messaging.Agent {
+		a := newAgent(representation2.Initialize(m), ex, service)
+		a.name = name
+		return a
 	})
 }
 
-func newAgent(state *representation1.Cache, ex rest.Exchange, service *operations.Service) *agentT {
+func newAgent(state *representation2.Cache, ex rest.Exchange, service *operations.Service) *agentT {
 	a := new(agentT)
-	a.state = state
+	a.name = NamespaceName
+	a.state.Store(state)
 	a.service = service
 	if ex == nil {
 		a.exchange = httpx.Do
 	} else {
 		a.exchange = ex
 	}
-	a.ticker = messaging.NewTicker(messaging.ChannelEmissary, a.state.Interval)
+	a.latency = latency.NewTracker(latency.DefaultSize)
+	a.controller = representation.NewController(a, representation2.Schema, service)
+	a.ticker = messaging.NewTicker(messaging.ChannelEmissary, a.state.Load().Interval)
 	a.emissary = messaging.NewEmissaryChannel()
 	return a
 }
@@ -67,27 +100,40 @@ func newAgent(state *representation1.Cache, ex rest.Exchange, service *operation
 func (a *agentT) String() string { return a.Name() }
 
 // Name - agent identifier
-func (a *agentT) Name() string { return NamespaceName }
+func (a *agentT) Name() string { return a.name }
 
 // Message - message the agent
 func (a *agentT) Message(m *messaging.Message) {
 	if m == nil {
 		return
 	}
-	if !a.state.Running {
+	if !a.running.Load() {
 		if m.Name == messaging.ConfigEvent {
 			a.configure(m)
 			return
 		}
+		if m.Name == config.QueryEvent {
+			config.Reply(m, a.Map())
+			return
+		}
+		if m.Name == config.HistoryEvent {
+			config.ReplyHistory(m, a.controller.History())
+			return
+		}
+		if m.Name == config.RollbackEvent {
+			messaging.Reply(m, a.controller.Rollback(m), a.Name())
+			return
+		}
 		if m.Name == messaging.StartupEvent {
+			a.controller.Resolve(true)
 			a.run()
-			a.state.Running = true
+			a.running.Store(true)
 			return
 		}
 		return
 	}
 	if m.Name == messaging.ShutdownEvent {
-		a.state.Running = false
+		a.running.Store(false)
 	}
 	a.emissary.C <- m
 }
@@ -100,7 +146,7 @@ func (a *agentT) run() {
 // Log - implementation for Requester interface
 func (a *agentT) Log() bool              { return true }
 func (a *agentT) Route() string          { return Route }
-func (a *agentT) Timeout() time.Duration { return a.state.Timeout }
+func (a *agentT) Timeout() time.Duration { return a.timeout() }
 func (a *agentT) Do() rest.Exchange      { return a.exchange }
 
 // Link - chainable exchange
@@ -110,19 +156,29 @@ func (a *agentT) Link(next rest.Exchange) rest.Exchange {
 			return next(r)
 		}
 		var (
-			url    string
 			status *messaging.Status
+			ok     bool
 		)
 		// cache lookup
-		url = uri.BuildURL(a.state.Host, r.URL.Path, r.URL.Query())
 		h := make(http.Header)
 		h.Add(httpx.XRequestId, r.Header.Get(httpx.XRequestId))
-		resp, status = request.Do(a, http.MethodGet, url, h, nil)
+		resp, status = a.lookup(r, h)
 		if resp.StatusCode == http.StatusOK {
-			resp.Header.Add(access2.XCached, "true")
-			return resp, nil
+			if resp, ok = restore(resp); ok {
+				if resp.StatusCode == http.StatusOK {
+					compression.AddVary(resp.Header)
+					resp.Header.Set(acceptRanges, bytesUnit)
+					resp, err = serveRange(r, resp)
+				}
+				resp.Header.Add(access2.XCached, "true")
+				return resp, err
+			}
 		}
 		resp.Header.Add(access2.XCached, "false")
+		// client disconnected or deadline exceeded
+		if r.Context().Err() != nil {
+			return resp, r.Context().Err()
+		}
 		if status.Err != nil {
 			a.service.Message(messaging.NewStatusMessage(status.WithLocation(a.Name()), a.Name()))
 		}
@@ -130,7 +186,12 @@ func (a *agentT) Link(next rest.Exchange) rest.Exchange {
 		resp, err = next(r)
 		if resp.StatusCode == http.StatusOK {
 			// cache update
-			err = a.cacheUpdate(url, r, resp)
+			err = a.cacheUpdate(r, resp)
+			if err != nil {
+				return serverErrorResponse, err
+			}
+		} else if ttl := a.negativeTTL(resp); ttl > 0 {
+			err = a.negativeUpdate(r, resp, ttl)
 			if err != nil {
 				return serverErrorResponse, err
 			}
@@ -139,6 +200,25 @@ func (a *agentT) Link(next rest.Exchange) rest.Exchange {
 	}
 }
 
+// timeout - static or adaptive timeout for the cache host
+func (a *agentT) timeout() time.Duration {
+	state := a.state.Load()
+	return state.Adaptive.Duration(a.latency.Histogram(state.Host), state.Timeout)
+}
+
+// do - cache request, observing the cache host latency
+func (a *agentT) do(ctx context.Context, method, url string, h http.Header, r io.ReadCloser) (*http.Response, *messaging.Status) {
+	start := time.Now().UTC()
+	resp, status := request.DoWithContext(ctx, a, method, url, h, r)
+	elapsed := time.Since(start)
+	// A timed out request is not observed, as it would raise the adaptive timeout
+	if status.Err == nil {
+		a.latency.Observe(a.state.Load().Host, elapsed)
+	}
+	a.controller.Observe(elapsed, probation.Failure(resp, status.Err))
+	return resp, status
+}
+
 func (a *agentT) trace(task, observation, action string) {
 	if a.review == nil {
 		return
@@ -155,12 +235,10 @@ func (a *agentT) trace(task, observation, action string) {
 func (a *agentT) configure(m *messaging.Message) {
 	switch m.ContentType() {
 	case messaging.ContentTypeMap:
-		cfg, status := messaging.MapContent(m)
-		if !status.OK() {
+		if status := a.controller.Configure(m); !status.OK() {
 			messaging.Reply(m, status, a.Name())
 			return
 		}
-		a.state.Update(cfg)
 	case messaging.ContentTypeReview:
 		r, status := messaging.ReviewContent(m)
 		if !status.OK() {
@@ -172,42 +250,33 @@ func (a *agentT) configure(m *messaging.Message) {
 	messaging.Reply(m, messaging.StatusOK(), a.Name())
 }
 
+// Update - implementation for representation.Agent interface, a configuration map is applied to a copy of the
+// representation, and requests in progress keep the current representation
+func (a *agentT) Update(m map[string]string) {
+	c := representation2.Initialize(a.state.Load().Map())
+	c.Update(m)
+	a.state.Store(c)
+}
+
+// Restore - implementation for representation.Agent interface
+func (a *agentT) Restore(m map[string]string) {
+	a.state.Store(representation2.Initialize(m))
+}
+
+// Map - implementation for representation.Agent interface
+func (a *agentT) Map() map[string]string { return a.state.Load().Map() }
+
 func (a *agentT) cacheable(r *http.Request) bool {
-	if a.state.Host == "" || r.Method != http.MethodGet || httpx.CacheControlNoCache(r.Header) {
+	state := a.state.Load()
+	if state.Host == "" || r.Method != http.MethodGet || httpx.CacheControlNoCache(r.Header) {
 		return false
 	}
-	return a.state.Enabled.Load()
+	// The schedule and the operator override are evaluated per request, so that a schedule boundary or an
+	// override expiration takes effect immediately
+	return state.Current()
 }
 
 func (a *agentT) emissaryShutdown() {
 	a.emissary.Close()
 	a.ticker.Stop()
 }
-
-func (a *agentT) cacheUpdate(url string, r *http.Request, resp *http.Response) error {
-	var (
-		buf    []byte
-		err    error
-		status *messaging.Status
-	)
-	// TODO: Need to reset the body in the response after reading it.
-	buf, err = io.ReadAll(resp.Body)
-	if err != nil {
-		status = messaging.NewStatus(messaging.StatusIOError, err).WithLocation(a.Name())
-		a.service.Message(messaging.NewStatusMessage(status, a.Name()))
-		return err
-	}
-	resp.ContentLength = int64(len(buf))
-	resp.Body = io.NopCloser(bytes.NewReader(buf))
-
-	// cache update
-	go func() {
-		h2 := httpx.CloneHeader(resp.Header)
-		h2.Add(httpx.XRequestId, r.Header.Get(httpx.XRequestId))
-		_, status = request.Do(a, http.MethodPut, url, h2, io.NopCloser(bytes.NewReader(buf)))
-		if status.Err != nil {
-			a.service.Message(messaging.NewStatusMessage(status.WithLocation(a.Name()), a.Name()))
-		}
-	}()
-	return nil
-}
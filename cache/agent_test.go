@@ -3,23 +3,29 @@ package cache
 import (
 	"fmt"
 	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/collective/repository"
+	"github.com/behavioral-ai/collective/resource"
 	"github.com/behavioral-ai/core/httpx"
 	"github.com/behavioral-ai/core/iox"
 	"github.com/behavioral-ai/core/messaging"
 	"github.com/behavioral-ai/core/rest"
 	"github.com/behavioral-ai/intermediary/cache/representation1"
+	"github.com/behavioral-ai/intermediary/cache/representation2"
+	"github.com/behavioral-ai/intermediary/config"
+	"github.com/behavioral-ai/intermediary/namespace"
 	"net/http"
+	"time"
 )
 
 func ExampleNew() {
 	//url := "https://www.google.com/search"
-	a := newAgent(representation1.Initialize(nil), nil, operationstest.NewService())
+	a := newAgent(representation2.Initialize(nil), nil, operationstest.NewService())
 
 	fmt.Printf("test: newAgent() -> %v\n", a.Name())
 	m := make(map[string]string)
 	m[representation1.HostKey] = "google.com"
 	a.Message(messaging.NewMapMessage(m))
-	fmt.Printf("test: Message() -> %v\n", a.state.Host)
+	fmt.Printf("test: Message() -> %v\n", a.state.Load().Host)
 
 	//Output:
 	//test: newAgent() -> test:resiliency:agent/cache/request/http
@@ -40,3 +46,111 @@ func routingExchange(next rest.Exchange) rest.Exchange {
 		return
 	}
 }
+
+func ExampleInstanceOverride() {
+	InstanceOverride("orders", map[string]string{representation1.HostKey: "orders.cache.com"}, nil, operationstest.NewService())
+	InstanceOverride("search", map[string]string{representation1.HostKey: "search.cache.com"}, nil, operationstest.NewService())
+
+	orders := repository.Agent(namespace.Instance(NamespaceName, "orders")).(*agentT)
+	search := repository.Agent(namespace.Instance(NamespaceName, "search")).(*agentT)
+	fmt.Printf("test: InstanceOverride() -> [%v] [host:%v]\n", orders.Name(), orders.state.Load().Host)
+	fmt.Printf("test: InstanceOverride() -> [%v] [host:%v]\n", search.Name(), search.state.Load().Host)
+	fmt.Printf("test: InstanceOverride() -> [ticker:%v] [emissary:%v]\n", orders.ticker != search.ticker, orders.emissary != search.emissary)
+
+	//Output:
+	//test: InstanceOverride() -> [test:resiliency:agent/cache/request/http#orders] [host:orders.cache.com]
+	//test: InstanceOverride() -> [test:resiliency:agent/cache/request/http#search] [host:search.cache.com]
+	//test: InstanceOverride() -> [ticker:true] [emissary:true]
+
+}
+
+func ExampleResolve() {
+	name := namespace.Instance(NamespaceName, "resolve")
+	resource.NewAgent()
+	resource.Resolver.AddRepresentation(name, representation1.Fragment, "author", map[string]string{
+		representation1.HostKey: "resolve.cache.com",
+		representation1.ModeKey: representation1.ModeOn,
+	})
+
+	a := newAgent(representation2.Initialize(nil), nil, operationstest.NewService())
+	a.controller.Resolve(false)
+	fmt.Printf("test: resolve() -> [%v] [host:%v] [enabled:%v]\n", a.Name(), a.state.Load().Host, a.state.Load().Current())
+
+	a.name = name
+	a.controller.Resolve(false)
+	fmt.Printf("test: resolve() -> [%v] [host:%v] [enabled:%v]\n", a.Name(), a.state.Load().Host, a.state.Load().Current())
+
+	//Output:
+	//test: resolve() -> [test:resiliency:agent/cache/request/http] [host:] [enabled:false]
+	//test: resolve() -> [test:resiliency:agent/cache/request/http#resolve] [host:resolve.cache.com] [enabled:true]
+
+}
+
+func ExampleQuery() {
+	a := newAgent(representation2.Initialize(nil), nil, operationstest.NewService())
+	a.Message(messaging.NewMapMessage(map[string]string{representation1.HostKey: "localhost:8082", representation1.TimeoutKey: "750ms"}))
+	m, status := config.Query(a, 0)
+	fmt.Printf("test: Query() -> [host:%v] [timeout:%v] [interval:%v] [status:%v]\n", m[representation1.HostKey], m[representation1.TimeoutKey], m[representation1.IntervalKey], status)
+
+	a.Message(messaging.StartupMessage)
+	a.Message(messaging.NewMapMessage(map[string]string{representation1.TimeoutKey: "1500ms"}))
+	m, status = config.Query(a, 0)
+	fmt.Printf("test: Query() -> [running:%v] [host:%v] [timeout:%v] [status:%v]\n", a.running.Load(), m[representation1.HostKey], m[representation1.TimeoutKey], status)
+	a.Message(messaging.ShutdownMessage)
+
+	//Output:
+	//test: Query() -> [host:localhost:8082] [timeout:750ms] [interval:30m] [status:OK]
+	//test: Query() -> [running:true] [host:localhost:8082] [timeout:1500ms] [status:OK]
+
+}
+
+func Example_cacheable() {
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.HostKey:       "localhost:8082",
+		representation1.ModeKey:       representation1.ModeOn,
+		representation1.ModeExpiryKey: "100ms",
+	}), nil, operationstest.NewService())
+	req, _ := http.NewRequest(http.MethodGet, "https://localhost:8081/search?q=golang", nil)
+	fmt.Printf("test: cacheable() -> %v\n", a.cacheable(req))
+
+	// An expired override returns to the schedule without waiting for the ticker
+	time.Sleep(time.Millisecond * 150)
+	fmt.Printf("test: cacheable(expired) -> %v\n", a.cacheable(req))
+
+	//Output:
+	//test: cacheable() -> true
+	//test: cacheable(expired) -> false
+
+}
+
+func Example_rollback() {
+	a := newAgent(representation2.Initialize(map[string]string{representation1.TimeoutKey: "750ms"}), nil, operationstest.NewService())
+	m := messaging.NewMapMessage(map[string]string{representation1.TimeoutKey: "5s"})
+	m.SetFrom("operator")
+	a.Message(m)
+	a.Message(messaging.NewMapMessage(map[string]string{representation1.ModeKey: representation1.ModeOn}))
+
+	entries, status := config.QueryHistory(a, 0)
+	for _, e := range entries {
+		fmt.Printf("test: QueryHistory() -> [version:%v] [source:%v] [changes:%v]\n", e.Version, e.Source, len(e.Changes))
+	}
+	fmt.Printf("test: QueryHistory() -> [status:%v]\n", status)
+
+	reply := func(m *messaging.Message) { fmt.Printf("test: Rollback() -> [%v]\n", m.Name) }
+	a.Message(config.NewRollbackMessage(1, reply))
+	fmt.Printf("test: Rollback(1) -> [timeout:%v] [mode:%v] [enabled:%v]\n", a.state.Load().Timeout, a.state.Load().Mode, a.state.Load().Current())
+
+	a.Message(config.NewRollbackMessage(0, nil))
+	e, _ := a.controller.History().Lookup(0)
+	fmt.Printf("test: Rollback(0) -> [timeout:%v] [mode:%v] [enabled:%v] [version:%v]\n", a.state.Load().Timeout, a.state.Load().Mode, a.state.Load().Current(), e.Version)
+
+	//Output:
+	//test: QueryHistory() -> [version:1] [source:initial] [changes:11]
+	//test: QueryHistory() -> [version:2] [source:operator] [changes:1]
+	//test: QueryHistory() -> [version:3] [source:unknown] [changes:1]
+	//test: QueryHistory() -> [status:OK]
+	//test: Rollback() -> [common:core:event/status]
+	//test: Rollback(1) -> [timeout:750ms] [mode:auto] [enabled:false]
+	//test: Rollback(0) -> [timeout:5s] [mode:on] [enabled:true] [version:4]
+
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/config"
 )
 
 // emissary attention
@@ -12,7 +13,7 @@ func emissaryAttend(a *agentT) {
 		select {
 		case <-a.ticker.C():
 			if !paused {
-				a.state.Enabled.Store(a.state.Now())
+				a.controller.Resolve(false)
 			}
 		default:
 		}
@@ -23,6 +24,14 @@ func emissaryAttend(a *agentT) {
 				paused = true
 			case messaging.ResumeEvent:
 				paused = false
+			case messaging.ConfigEvent:
+				a.configure(msg)
+			case config.QueryEvent:
+				config.Reply(msg, a.Map())
+			case config.HistoryEvent:
+				config.ReplyHistory(msg, a.controller.History())
+			case config.RollbackEvent:
+				messaging.Reply(msg, a.controller.Rollback(msg), a.Name())
 			case messaging.ShutdownEvent:
 				a.emissaryShutdown()
 				return
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/compression"
+	"github.com/behavioral-ai/intermediary/request"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	XCacheStatusCode = "X-Cache-Status-Code" // Status code of a negative cache entry, stored entries are always 200
+	XCacheExpires    = "X-Cache-Expires"     // Expiration of a negative cache entry, HTTP date
+	cacheControl     = "Cache-Control"
+)
+
+// negativeTTL - time to live of a cacheable error or redirect response, bounded by the response Cache-Control
+func (a *agentT) negativeTTL(resp *http.Response) time.Duration {
+	ttl := a.state.Load().Negative.TTL(resp.StatusCode)
+	if ttl <= 0 {
+		return 0
+	}
+	for _, v := range resp.Header.Values(cacheControl) {
+		for _, directive := range strings.Split(v, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			switch strings.ToLower(name) {
+			case "no-store", "private":
+				return 0
+			case "max-age", "s-maxage":
+				secs, err := strconv.Atoi(strings.Trim(value, "\""))
+				if err != nil {
+					continue
+				}
+				ttl = min(ttl, time.Duration(secs)*time.Second)
+			}
+		}
+	}
+	return max(ttl, 0)
+}
+
+// negativeUpdate - store an error or redirect response as the identity entry, with its status code and expiration.
+// The entry also replaces the compressed variant, which is looked up first, so that a previous response is not
+// served to clients that accept the variant encoding.
+func (a *agentT) negativeUpdate(r *http.Request, resp *http.Response, ttl time.Duration) error {
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		status := messaging.NewStatus(messaging.StatusIOError, err).WithLocation(a.Name())
+		a.service.Message(messaging.NewStatusMessage(status, a.Name()))
+		return err
+	}
+	resp.Body.Close()
+	request.SetBody(resp, buf)
+	if !compression.Supported(resp.Header.Get(compression.ContentEncoding)) {
+		return nil
+	}
+	h := httpx.CloneHeader(resp.Header)
+	h.Add(httpx.XRequestId, r.Header.Get(httpx.XRequestId))
+	h.Del(contentLength)
+	h.Set(XCacheStatusCode, strconv.Itoa(resp.StatusCode))
+	h.Set(XCacheExpires, time.Now().Add(ttl).UTC().Format(http.TimeFormat))
+	state := a.state.Load()
+	go func() {
+		a.put(variantURL(state.Host, r, ""), h, buf)
+		if state.Variant != "" {
+			a.put(variantURL(state.Host, r, state.Variant), h, buf)
+		}
+	}()
+	return nil
+}
+
+// restore - restore the status code of a cache entry, an expired entry is a miss
+func restore(resp *http.Response) (*http.Response, bool) {
+	if s := resp.Header.Get(XCacheExpires); s != "" {
+		expires, err := http.ParseTime(s)
+		if err != nil || !time.Now().Before(expires) {
+			request.Discard(resp)
+			return httpx.NewResponse(http.StatusNotFound, nil, nil), false
+		}
+		resp.Header.Del(XCacheExpires)
+	}
+	if s := resp.Header.Get(XCacheStatusCode); s != "" {
+		if code, err := strconv.Atoi(s); err == nil {
+			resp.StatusCode = code
+		}
+		resp.Header.Del(XCacheStatusCode)
+	}
+	return resp, true
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/core/access2"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/intermediary/cache/representation1"
+	"github.com/behavioral-ai/intermediary/cache/representation2"
+	"github.com/behavioral-ai/intermediary/compression"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+func notFoundExchange(r *http.Request) (*http.Response, error) {
+	h := make(http.Header)
+	switch r.URL.Path {
+	case "/private":
+		h.Set(cacheControl, "private")
+	case "/error":
+		return httpx.NewResponse(http.StatusServiceUnavailable, h, nil), nil
+	}
+	return httpx.NewResponse(http.StatusNotFound, h, []byte("not found")), nil
+}
+
+func Example_negativeTTL() {
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.NotFoundTTLKey:    "5m",
+		representation1.ServerErrorTTLKey: "5s",
+	}), nil, operationstest.NewService())
+
+	ttl := func(code int, cc string) time.Duration {
+		h := make(http.Header)
+		if cc != "" {
+			h.Set(cacheControl, cc)
+		}
+		return a.negativeTTL(httpx.NewResponse(code, h, nil))
+	}
+	fmt.Printf("test: negativeTTL(404) -> %v\n", ttl(http.StatusNotFound, ""))
+	fmt.Printf("test: negativeTTL(410,max-age=60) -> %v\n", ttl(http.StatusGone, "public, max-age=60"))
+	fmt.Printf("test: negativeTTL(404,no-store) -> %v\n", ttl(http.StatusNotFound, "no-store"))
+	fmt.Printf("test: negativeTTL(503) -> %v\n", ttl(http.StatusServiceUnavailable, ""))
+	fmt.Printf("test: negativeTTL(301) -> %v\n", ttl(http.StatusMovedPermanently, ""))
+	fmt.Printf("test: negativeTTL(400) -> %v\n", ttl(http.StatusBadRequest, ""))
+
+	//Output:
+	//test: negativeTTL(404) -> 5m0s
+	//test: negativeTTL(410,max-age=60) -> 1m0s
+	//test: negativeTTL(404,no-store) -> 0s
+	//test: negativeTTL(503) -> 5s
+	//test: negativeTTL(301) -> 0s
+	//test: negativeTTL(400) -> 0s
+
+}
+
+func Example_linkNegative() {
+	c := &memoryCache{entries: make(map[string]entry)}
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.HostKey:        "localhost:8082",
+		representation1.NotFoundTTLKey: "1m",
+		representation1.ModeKey:        representation1.ModeOn,
+	}), c.exchange, operationstest.NewService())
+	ex := a.Link(notFoundExchange)
+
+	send := func(path string) {
+		req, _ := http.NewRequest(http.MethodGet, "https://localhost:8081"+path, nil)
+		resp, _ := ex(req)
+		fmt.Printf("test: Link(%v) -> [status:%v] [cached:%v] [status-code:%v]\n", path, resp.StatusCode, resp.Header.Get(access2.XCached), resp.Header.Get(XCacheStatusCode))
+	}
+	send("/missing")
+	send("/private")
+	send("/error")
+	time.Sleep(time.Millisecond * 100)
+	fmt.Printf("test: negativeUpdate() -> %v\n", c.keys())
+
+	send("/missing")
+	send("/private")
+
+	// Expire the entry
+	for k, e := range c.entries {
+		e.header.Set(XCacheExpires, time.Now().Add(-time.Second).UTC().Format(http.TimeFormat))
+		c.entries[k] = e
+	}
+	send("/missing")
+
+	//Output:
+	//test: Link(/missing) -> [status:404] [cached:] [status-code:]
+	//test: Link(/private) -> [status:404] [cached:] [status-code:]
+	//test: Link(/error) -> [status:503] [cached:] [status-code:]
+	//test: negativeUpdate() -> [https://localhost:8082/missing?x-cache-encoding=gzip https://localhost:8082/missing?x-cache-encoding=identity]
+	//test: Link(/missing) -> [status:404] [cached:true] [status-code:]
+	//test: Link(/private) -> [status:404] [cached:] [status-code:]
+	//test: Link(/missing) -> [status:404] [cached:] [status-code:]
+
+}
+
+func Example_linkNegativeVariant() {
+	c := &memoryCache{entries: make(map[string]entry)}
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.HostKey:        "localhost:8082",
+		representation1.NotFoundTTLKey: "1m",
+		representation1.ModeKey:        representation1.ModeOn,
+	}), c.exchange, operationstest.NewService())
+	var gone atomic.Bool
+	ex := a.Link(func(r *http.Request) (*http.Response, error) {
+		if gone.Load() {
+			return notFoundExchange(r)
+		}
+		return gzipExchange(r)
+	})
+
+	send := func(accept string) {
+		req, _ := http.NewRequest(http.MethodGet, "https://localhost:8081/search?q=golang", nil)
+		if accept != "" {
+			req.Header.Set(compression.AcceptEncoding, accept)
+		}
+		resp, _ := ex(req)
+		fmt.Printf("test: Link(%v) -> [status:%v] [cached:%v]\n", accept, resp.StatusCode, resp.Header.Get(access2.XCached))
+	}
+	send("gzip")
+	time.Sleep(time.Millisecond * 100)
+
+	// The identity entry is evicted, and the upstream now returns not found
+	c.mu.Lock()
+	delete(c.entries, "https://localhost:8082/search?q=golang&x-cache-encoding=identity")
+	c.mu.Unlock()
+	gone.Store(true)
+	send("")
+	time.Sleep(time.Millisecond * 100)
+	send("gzip")
+
+	//Output:
+	//test: Link(gzip) -> [status:200] [cached:]
+	//test: Link() -> [status:404] [cached:]
+	//test: Link(gzip) -> [status:404] [cached:true]
+
+}
@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/intermediary/request"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// Range requests are served from cached complete entries. Partial content, 206, responses from the upstream
+// are not cached, as a cache entry must be a complete representation, so on a cache miss the Range header is
+// forwarded and the upstream response is returned as is.
+
+const (
+	rangeHeader   = "Range"
+	ifRange       = "If-Range"
+	acceptRanges  = "Accept-Ranges"
+	contentRange  = "Content-Range"
+	contentType   = "Content-Type"
+	etag          = "ETag"
+	lastModified  = "Last-Modified"
+	bytesUnit     = "bytes"
+	maxRanges     = 32
+	multipartType = "multipart/byteranges; boundary="
+)
+
+var (
+	errRangeSyntax = errors.New("invalid range")
+)
+
+type byteRange struct {
+	start, end int64 // Inclusive
+}
+
+func (r byteRange) length() int64 { return r.end - r.start + 1 }
+
+func (r byteRange) contentRange(size int64) string {
+	return fmt.Sprintf("%v %v-%v/%v", bytesUnit, r.start, r.end, size)
+}
+
+// serveRange - create a partial content response from a cached complete entry. The entry is returned unchanged
+// if there is no Range header, the range is invalid, or the If-Range validator does not match.
+func serveRange(r *http.Request, resp *http.Response) (*http.Response, error) {
+	s := r.Header.Get(rangeHeader)
+	if s == "" || r.Method != http.MethodGet || !ifRangeMatch(r.Header.Get(ifRange), resp.Header) {
+		return resp, nil
+	}
+	buf, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return resp, err
+	}
+	request.SetBody(resp, buf)
+	size := int64(len(buf))
+	ranges, err := parseRange(s, size)
+	if err != nil {
+		return resp, nil
+	}
+	if len(ranges) == 0 {
+		h := make(http.Header)
+		h.Set(contentRange, fmt.Sprintf("%v */%v", bytesUnit, size))
+		return httpx.NewResponse(http.StatusRequestedRangeNotSatisfiable, h, nil), nil
+	}
+	h := httpx.CloneHeader(resp.Header)
+	h.Set(acceptRanges, bytesUnit)
+	if len(ranges) == 1 {
+		rng := ranges[0]
+		h.Set(contentRange, rng.contentRange(size))
+		partial := httpx.NewResponse(http.StatusPartialContent, h, nil)
+		request.SetBody(partial, buf[rng.start:rng.end+1])
+		return partial, nil
+	}
+	var out bytes.Buffer
+	w := multipart.NewWriter(&out)
+	for _, rng := range ranges {
+		ph := make(textproto.MIMEHeader)
+		if ct := resp.Header.Get(contentType); ct != "" {
+			ph.Set(contentType, ct)
+		}
+		ph.Set(contentRange, rng.contentRange(size))
+		part, err1 := w.CreatePart(ph)
+		if err1 != nil {
+			return resp, err1
+		}
+		part.Write(buf[rng.start : rng.end+1])
+	}
+	w.Close()
+	h.Set(contentType, multipartType+w.Boundary())
+	h.Del(contentRange)
+	partial := httpx.NewResponse(http.StatusPartialContent, h, nil)
+	request.SetBody(partial, out.Bytes())
+	return partial, nil
+}
+
+// ifRangeMatch - determine if the If-Range validator matches the entry, RFC 9110 13.1.5. An entity tag must
+// be a strong match, and a date must exactly match the Last-Modified date.
+func ifRangeMatch(validator string, h http.Header) bool {
+	validator = strings.TrimSpace(validator)
+	if validator == "" {
+		return true
+	}
+	if strings.HasPrefix(validator, "\"") {
+		tag := h.Get(etag)
+		return tag != "" && !strings.HasPrefix(tag, "W/") && tag == validator
+	}
+	if strings.HasPrefix(validator, "W/") {
+		return false
+	}
+	t, err := http.ParseTime(validator)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(h.Get(lastModified))
+	return err == nil && t.Equal(modified)
+}
+
+// parseRange - parse a Range header value, RFC 9110 14.1.2. An error is returned for an invalid range, which is
+// ignored, and an empty list for a range that cannot be satisfied.
+func parseRange(s string, size int64) ([]byteRange, error) {
+	unit, set, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(unit) != bytesUnit {
+		return nil, errRangeSyntax
+	}
+	var ranges []byteRange
+	specs := strings.Split(set, ",")
+	if len(specs) > maxRanges {
+		return nil, errRangeSyntax
+	}
+	count := 0
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		count++
+		first, last, ok1 := strings.Cut(spec, "-")
+		if !ok1 {
+			return nil, errRangeSyntax
+		}
+		first = strings.TrimSpace(first)
+		last = strings.TrimSpace(last)
+		var rng byteRange
+		if first == "" {
+			// Suffix range, the last N bytes
+			n, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || n < 0 {
+				return nil, errRangeSyntax
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+			rng = byteRange{start: max(size-n, 0), end: size - 1}
+		} else {
+			start, err := strconv.ParseInt(first, 10, 64)
+			if err != nil || start < 0 {
+				return nil, errRangeSyntax
+			}
+			end := size - 1
+			if last != "" {
+				end, err = strconv.ParseInt(last, 10, 64)
+				if err != nil || end < start {
+					return nil, errRangeSyntax
+				}
+			}
+			if start >= size {
+				continue
+			}
+			rng = byteRange{start: start, end: min(end, size-1)}
+		}
+		ranges = append(ranges, rng)
+	}
+	if count == 0 {
+		return nil, errRangeSyntax
+	}
+	return ranges, nil
+}
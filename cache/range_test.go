@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/core/httpx"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+)
+
+func Example_parseRange() {
+	for _, s := range []string{"bytes=0-9", "bytes=90-", "bytes=-20", "bytes=95-200", "bytes=0-0, -1", "bytes=200-", "items=0-9", "bytes=9-0", "bytes="} {
+		ranges, err := parseRange(s, 100)
+		fmt.Printf("test: parseRange(%v) -> %v [err:%v]\n", s, ranges, err)
+	}
+
+	//Output:
+	//test: parseRange(bytes=0-9) -> [{0 9}] [err:<nil>]
+	//test: parseRange(bytes=90-) -> [{90 99}] [err:<nil>]
+	//test: parseRange(bytes=-20) -> [{80 99}] [err:<nil>]
+	//test: parseRange(bytes=95-200) -> [{95 99}] [err:<nil>]
+	//test: parseRange(bytes=0-0, -1) -> [{0 0} {99 99}] [err:<nil>]
+	//test: parseRange(bytes=200-) -> [] [err:<nil>]
+	//test: parseRange(items=0-9) -> [] [err:invalid range]
+	//test: parseRange(bytes=9-0) -> [] [err:invalid range]
+	//test: parseRange(bytes=) -> [] [err:invalid range]
+
+}
+
+func Example_serveRange() {
+	content := "0123456789abcdefghijklmnopqrstuvwxyz"
+	entry := func() *http.Response {
+		h := make(http.Header)
+		h.Set(contentType, "text/plain")
+		h.Set(etag, "\"v1\"")
+		h.Set(lastModified, "Mon, 02 Jan 2006 15:04:05 GMT")
+		return httpx.NewResponse(http.StatusOK, h, []byte(content))
+	}
+	send := func(rng, validator string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, "https://localhost:8081/media", nil)
+		req.Header.Set(rangeHeader, rng)
+		if validator != "" {
+			req.Header.Set(ifRange, validator)
+		}
+		resp, _ := serveRange(req, entry())
+		return resp
+	}
+
+	resp := send("bytes=10-15", "")
+	buf, _ := io.ReadAll(resp.Body)
+	fmt.Printf("test: serveRange() -> [status:%v] [content-range:%v] [length:%v] [body:%v]\n", resp.StatusCode, resp.Header.Get(contentRange), resp.ContentLength, string(buf))
+
+	resp = send("bytes=100-", "")
+	fmt.Printf("test: serveRange() -> [status:%v] [content-range:%v]\n", resp.StatusCode, resp.Header.Get(contentRange))
+
+	resp = send("bytes=0-3", "\"v2\"")
+	fmt.Printf("test: serveRange() -> [if-range:\"v2\"] [status:%v]\n", resp.StatusCode)
+
+	resp = send("bytes=0-3", "\"v1\"")
+	fmt.Printf("test: serveRange() -> [if-range:\"v1\"] [status:%v]\n", resp.StatusCode)
+
+	resp = send("bytes=0-3", "Mon, 02 Jan 2006 15:04:05 GMT")
+	fmt.Printf("test: serveRange() -> [if-range:date] [status:%v]\n", resp.StatusCode)
+
+	resp = send("bytes=0-1,-2", "")
+	mt, params, _ := mime.ParseMediaType(resp.Header.Get(contentType))
+	fmt.Printf("test: serveRange() -> [status:%v] [content-type:%v]\n", resp.StatusCode, mt)
+	r := multipart.NewReader(resp.Body, params["boundary"])
+	for {
+		part, err := r.NextPart()
+		if err != nil {
+			break
+		}
+		buf, _ = io.ReadAll(part)
+		fmt.Printf("test: serveRange() -> [part] [content-type:%v] [content-range:%v] [body:%v]\n", part.Header.Get(contentType), part.Header.Get(contentRange), string(buf))
+	}
+
+	//Output:
+	//test: serveRange() -> [status:206] [content-range:bytes 10-15/36] [length:6] [body:abcdef]
+	//test: serveRange() -> [status:416] [content-range:bytes */36]
+	//test: serveRange() -> [if-range:"v2"] [status:200]
+	//test: serveRange() -> [if-range:"v1"] [status:206]
+	//test: serveRange() -> [if-range:date] [status:206]
+	//test: serveRange() -> [status:206] [content-type:multipart/byteranges]
+	//test: serveRange() -> [part] [content-type:text/plain] [content-range:bytes 0-1/36] [body:01]
+	//test: serveRange() -> [part] [content-type:text/plain] [content-range:bytes 34-35/36] [body:yz]
+
+}
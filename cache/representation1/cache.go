@@ -2,10 +2,13 @@ package representation1
 
 import (
 	"github.com/behavioral-ai/core/fmtx"
+	"github.com/behavioral-ai/intermediary/compression"
+	"github.com/behavioral-ai/intermediary/config"
+	"github.com/behavioral-ai/intermediary/latency"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
-	"sync/atomic"
 	"time"
 )
 
@@ -22,42 +25,59 @@ const (
 	ThursdayKey     = "thu"
 	FridayKey       = "fri"
 	SaturdayKey     = "sat"
-	rangeSeparator  = "-"
+	ModeKey         = "mode"
+	ModeExpiryKey   = "mode-expiry"
+	VariantKey      = "variant"
+
+	RedirectTTLKey    = "ttl-redirect"
+	NotFoundTTLKey    = "ttl-not-found"
+	ServerErrorTTLKey = "ttl-server-error"
+
+	TimeoutModeKey       = latency.TimeoutModeKey
+	TimeoutPercentileKey = latency.TimeoutPercentileKey
+	TimeoutFactorKey     = latency.TimeoutFactorKey
+	TimeoutMinKey        = latency.TimeoutMinKey
+	TimeoutMaxKey        = latency.TimeoutMaxKey
+
+	rangeSeparator = "-"
+	VariantNone    = "none"
+
+	ModeAuto = "auto" // Caching follows the day/hour schedule
+	ModeOn   = "on"   // Caching is forced on
+	ModeOff  = "off"  // Caching is forced off
 
 	defaultInterval = time.Minute * 30
 	defaultTimeout  = time.Millisecond * 2000
+	defaultVariant  = compression.GzipEncoding
 )
 
 type Cache struct {
-	Running  bool
-	Enabled  *atomic.Bool
 	Timeout  time.Duration
 	Interval time.Duration
 	Host     string           // User requirement
 	Policy   http.Header      // User requirement
 	Days     map[string]Range // User requirement
+	Mode     string           // Operator override
+	Expiry   time.Time        // Operator override expiration, zero for no expiration
+	Adaptive latency.Timeout  // Adaptive timeout, Timeout is used when static
+	Variant  string           // Compressed variant stored with the identity entry, empty for none
+	Negative Negative         // Error and redirect response caching
 }
 
 // Initialize - add a default policy
 func Initialize(m map[string]string) *Cache {
 	c := new(Cache)
-	c.Enabled = new(atomic.Bool)
-	c.Enabled.Store(false)
 	c.Timeout = defaultTimeout
 	c.Interval = defaultInterval
+	c.Mode = ModeAuto
+	c.Adaptive = latency.NewTimeout()
+	c.Variant = defaultVariant
 	c.Policy = make(http.Header)
 	c.Days = make(map[string]Range)
 	parseCache(c, m)
 	return c
 }
 
-/*
-func NewCache(name string) *Cache {
-	//m, _ := resource.Resolve[map[string]string](name, Fragment, resource.Resolver)
-	return newCache(nil)
-}
-*/
-
 func newCache(m map[string]string) *Cache {
 	c := Initialize(m)
 	return c
@@ -86,10 +106,57 @@ func (c *Cache) Now() bool {
 	return c.Days[s].In(ts)
 }
 
+// Override - returns the operator override, and whether it is in effect at the given time
+func (c *Cache) Override(ts time.Time) (enabled bool, ok bool) {
+	if c.Mode != ModeOn && c.Mode != ModeOff {
+		return false, false
+	}
+	if !c.Expiry.IsZero() && ts.After(c.Expiry) {
+		return false, false
+	}
+	return c.Mode == ModeOn, true
+}
+
+// Current - an override in effect takes precedence over the schedule
+func (c *Cache) Current() bool {
+	if enabled, ok := c.Override(time.Now()); ok {
+		return enabled
+	}
+	return c.Now()
+}
+
 func (c *Cache) Update(m map[string]string) {
 	parseCache(c, m)
 }
 
+// Map - export the configuration, including defaults, as a configuration map. Parsing the map returns the same
+// configuration, runtime state is not included.
+func (c *Cache) Map() map[string]string {
+	m := make(map[string]string)
+	if c.Host != "" {
+		m[HostKey] = c.Host
+	}
+	if s := c.Policy.Get(CacheControlKey); s != "" {
+		m[CacheControlKey] = s
+	}
+	m[TimeoutKey] = config.FormatDuration(c.Timeout)
+	m[IntervalKey] = config.FormatDuration(c.Interval)
+	for day, r := range c.Days {
+		m[day] = formatRange(r)
+	}
+	m[ModeKey] = c.Mode
+	if !c.Expiry.IsZero() && c.Mode != ModeAuto {
+		m[ModeExpiryKey] = c.Expiry.Format(time.RFC3339Nano)
+	}
+	latency.FormatTimeout(c.Adaptive, m)
+	formatNegative(c.Negative, m)
+	m[VariantKey] = c.Variant
+	if c.Variant == "" {
+		m[VariantKey] = VariantNone
+	}
+	return m
+}
+
 func parseCache(c *Cache, m map[string]string) {
 	if c == nil || m == nil {
 		return
@@ -127,6 +194,15 @@ func parseCache(c *Cache, m map[string]string) {
 		c.Interval = dur
 	}
 	parseDays(c, m)
+	parseMode(c, m)
+	latency.ParseTimeout(&c.Adaptive, m)
+	parseNegative(&c.Negative, m)
+	s = m[VariantKey]
+	if s == VariantNone {
+		c.Variant = ""
+	} else if slices.Contains(compression.Encodings, s) {
+		c.Variant = s
+	}
 }
 
 func parseDays(c *Cache, m map[string]string) {
@@ -150,6 +226,34 @@ func parseDay(c *Cache, key string, m map[string]string) {
 	}
 }
 
+func parseMode(c *Cache, m map[string]string) {
+	s := m[ModeKey]
+	if s == "" {
+		return
+	}
+	switch s {
+	case ModeAuto, ModeOn, ModeOff:
+	default:
+		return
+	}
+	c.Mode = s
+	c.Expiry = time.Time{}
+	s = m[ModeExpiryKey]
+	if s == "" || c.Mode == ModeAuto {
+		return
+	}
+	// Expiration is either a timestamp or a duration from now
+	if ts, err := time.Parse(time.RFC3339, s); err == nil {
+		c.Expiry = ts
+		return
+	}
+	dur, err := fmtx.ParseDuration(s)
+	if err != nil {
+		return
+	}
+	c.Expiry = time.Now().Add(dur)
+}
+
 // Range - hour range
 type Range struct {
 	From int
@@ -184,6 +288,11 @@ func (r Range) Empty() bool {
 	return r.From > r.To
 }
 
+// formatRange - format "from-to"
+func formatRange(r Range) string {
+	return strconv.Itoa(r.From) + rangeSeparator + strconv.Itoa(r.To)
+}
+
 func (r Range) In(ts time.Time) bool {
 	hour := ts.Hour()
 	return r.From <= hour && hour <= r.To
@@ -2,7 +2,8 @@ package representation1
 
 import (
 	"fmt"
-	"github.com/behavioral-ai/collective/resource"
+	"maps"
+	"reflect"
 	"time"
 )
 
@@ -45,31 +46,43 @@ func ExampleParseCache() {
 	fmt.Printf("test: parseCache() -> %v\n", cache)
 
 	//Output:
-	//test: parseCache() -> {false <nil> 750ms 4m0s www.google.com map[Cache-Control:[no-store, no-cache, max-age=0]] map[fri:{22 23} mon:{8 16} sat:{3 8} sun:{13 15} thu:{0 23} tue:{6 10} wed:{12 12}]}
+	//test: parseCache() -> {750ms 4m0s www.google.com map[Cache-Control:[no-store, no-cache, max-age=0]] map[fri:{22 23} mon:{8 16} sat:{3 8} sun:{13 15} thu:{0 23} tue:{6 10} wed:{12 12}]  0001-01-01 00:00:00 +0000 UTC { 0 0 0s 0s}  {0s 0s 0s}}
 
 }
 
-func _ExampleNewCache() {
-	resource.NewAgent()
+func ExampleCache_Override() {
+	c := Initialize(nil)
+	ok := false
+	enabled := false
+	_, ok = c.Override(time.Now())
+	fmt.Printf("test: Override() -> [mode:%v] [ok:%v]\n", c.Mode, ok)
 
-	status := resource.Resolver.AddRepresentation(NamespaceName, Fragment, "author", m)
-	fmt.Printf("test: AddRepresentation() -> [status:%v]\n", status)
+	c.Update(map[string]string{ModeKey: ModeOn})
+	enabled, ok = c.Override(time.Now())
+	fmt.Printf("test: Override() -> [mode:%v] [enabled:%v] [ok:%v] [current:%v]\n", c.Mode, enabled, ok, c.Current())
 
-	ct, status2 := resource.Resolver.Representation(NamespaceName)
-	fmt.Printf("test: Representation() -> [ct:%v] [status:%v]\n", ct, status2)
+	c.Update(map[string]string{ModeKey: ModeOff, ModeExpiryKey: "30m"})
+	enabled, ok = c.Override(time.Now())
+	fmt.Printf("test: Override() -> [mode:%v] [enabled:%v] [ok:%v] [current:%v]\n", c.Mode, enabled, ok, c.Current())
 
-	if buf, ok := ct.Value.([]byte); ok {
-		fmt.Printf("test: Representation() -> [value:%v] [status:%v]\n", len(buf), status2)
-	}
+	_, ok = c.Override(time.Now().Add(time.Hour))
+	fmt.Printf("test: Override() -> [mode:%v] [expired:%v]\n", c.Mode, !ok)
+
+	c.Update(map[string]string{ModeKey: ModeOn, ModeExpiryKey: "2025-01-01T00:00:00Z"})
+	_, ok = c.Override(time.Now())
+	fmt.Printf("test: Override() -> [mode:%v] [expiry:%v] [ok:%v]\n", c.Mode, c.Expiry, ok)
 
-	//l := NewCache(NamespaceName)
-	//fmt.Printf("test: NewCache() -> %v\n", l)
+	c.Update(map[string]string{ModeKey: ModeAuto})
+	_, ok = c.Override(time.Now())
+	fmt.Printf("test: Override() -> [mode:%v] [ok:%v]\n", c.Mode, ok)
 
 	//Output:
-	//test: AddRepresentation() -> [status:OK]
-	//test: Representation() -> [ct:fragment: v1 type: application/json value: true] [status:OK]
-	//test: Representation() -> [value:200] [status:OK]
-	//test: NewCache() -> &{false 0xc00010e138 www.google.com 750ms 4m0s map[Cache-Control:[no-store, no-cache, max-age=0]] map[fri:{22 23} mon:{8 16} sat:{3 8} sun:{13 15} thu:{0 23} tue:{6 10} wed:{12 12}]}
+	//test: Override() -> [mode:auto] [ok:false]
+	//test: Override() -> [mode:on] [enabled:true] [ok:true] [current:true]
+	//test: Override() -> [mode:off] [enabled:false] [ok:true] [current:false]
+	//test: Override() -> [mode:off] [expired:true]
+	//test: Override() -> [mode:on] [expiry:2025-01-01 00:00:00 +0000 UTC] [ok:false]
+	//test: Override() -> [mode:auto] [ok:false]
 
 }
 
@@ -112,3 +125,24 @@ func _ExampleRange() {
 	//test: NewRange("19-23") -> [hour:18] [in:false]
 
 }
+
+func ExampleCache_Map() {
+	c := Initialize(m)
+	c.Update(map[string]string{VariantKey: VariantNone, NotFoundTTLKey: "5m", TimeoutModeKey: "adaptive", TimeoutFactorKey: "1.5"})
+	m2 := c.Map()
+	fmt.Printf("test: Map() -> [timeout:%v] [interval:%v] [mon:%v] [variant:%v] [ttl-not-found:%v] [timeout-factor:%v] [keys:%v]\n",
+		m2[TimeoutKey], m2[IntervalKey], m2[MondayKey], m2[VariantKey], m2[NotFoundTTLKey], m2[TimeoutFactorKey], len(m2))
+
+	c2 := Initialize(m2)
+	fmt.Printf("test: Initialize(Map()) -> [equal:%v] [deep-equal:%v]\n", maps.Equal(m2, c2.Map()), reflect.DeepEqual(c, c2))
+
+	m2 = Initialize(nil).Map()
+	fmt.Printf("test: Map() -> [host:%v] [mode:%v] [variant:%v] [timeout:%v] [ttl-redirect:%v]\n",
+		m2[HostKey], m2[ModeKey], m2[VariantKey], m2[TimeoutKey], m2[RedirectTTLKey])
+
+	//Output:
+	//test: Map() -> [timeout:750ms] [interval:4m] [mon:8-16] [variant:none] [ttl-not-found:5m] [timeout-factor:1.5] [keys:21]
+	//test: Initialize(Map()) -> [equal:true] [deep-equal:true]
+	//test: Map() -> [host:] [mode:auto] [variant:gzip] [timeout:2s] [ttl-redirect:0ms]
+
+}
@@ -0,0 +1,51 @@
+package representation1
+
+import (
+	"github.com/behavioral-ai/core/fmtx"
+	"github.com/behavioral-ai/intermediary/config"
+	"net/http"
+	"time"
+)
+
+// Negative - time to live of cached error and redirect responses, by status class, zero disables caching
+type Negative struct {
+	Redirect    time.Duration // 301 and 308 permanent redirects
+	NotFound    time.Duration // 404 and 410
+	ServerError time.Duration // 500, 502, 503 and 504, should be very short
+}
+
+// TTL - time to live for a status code, zero if the status code is not cached
+func (n Negative) TTL(code int) time.Duration {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusPermanentRedirect:
+		return n.Redirect
+	case http.StatusNotFound, http.StatusGone:
+		return n.NotFound
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return n.ServerError
+	}
+	return 0
+}
+
+func parseNegative(n *Negative, m map[string]string) {
+	parseTTL(&n.Redirect, m[RedirectTTLKey])
+	parseTTL(&n.NotFound, m[NotFoundTTLKey])
+	parseTTL(&n.ServerError, m[ServerErrorTTLKey])
+}
+
+func formatNegative(n Negative, m map[string]string) {
+	m[RedirectTTLKey] = config.FormatDuration(n.Redirect)
+	m[NotFoundTTLKey] = config.FormatDuration(n.NotFound)
+	m[ServerErrorTTLKey] = config.FormatDuration(n.ServerError)
+}
+
+func parseTTL(d *time.Duration, s string) {
+	if s == "" {
+		return
+	}
+	dur, err := fmtx.ParseDuration(s)
+	if err != nil || dur < 0 {
+		return
+	}
+	*d = dur
+}
@@ -0,0 +1,99 @@
+package representation2
+
+import (
+	"github.com/behavioral-ai/intermediary/cache/representation1"
+	"github.com/behavioral-ai/intermediary/representation"
+)
+
+// Version 2 groups the day and hour schedule, and the negative caching time to live policy, into single keys.
+//
+//	schedule   : "mon:8-16|tue:6-10"
+//	ttl-policy : "redirect:1h|not-found:5m|server-error:5s"
+
+const (
+	Fragment     = "v2"
+	ScheduleKey  = "schedule"
+	TTLPolicyKey = "ttl-policy"
+
+	RedirectClass    = "redirect"
+	NotFoundClass    = "not-found"
+	ServerErrorClass = "server-error"
+)
+
+var (
+	schedule = []representation.Field{
+		{Name: representation1.SundayKey, Key: representation1.SundayKey},
+		{Name: representation1.MondayKey, Key: representation1.MondayKey},
+		{Name: representation1.TuesdayKey, Key: representation1.TuesdayKey},
+		{Name: representation1.WednesdayKey, Key: representation1.WednesdayKey},
+		{Name: representation1.ThursdayKey, Key: representation1.ThursdayKey},
+		{Name: representation1.FridayKey, Key: representation1.FridayKey},
+		{Name: representation1.SaturdayKey, Key: representation1.SaturdayKey},
+	}
+	ttlPolicy = []representation.Field{
+		{Name: RedirectClass, Key: representation1.RedirectTTLKey},
+		{Name: NotFoundClass, Key: representation1.NotFoundTTLKey},
+		{Name: ServerErrorClass, Key: representation1.ServerErrorTTLKey},
+	}
+
+	// Schema - cache representation versions
+	Schema = representation.Schema{
+		{Fragment: representation1.Fragment},
+		{Fragment: Fragment, Upgrade: upgrade, Validate: validate},
+	}
+)
+
+// Cache - version 2 representation, the version 1 representation configured with grouped keys
+type Cache struct {
+	representation1.Cache
+}
+
+// Initialize - create a representation from a configuration map of any version, an invalid map is ignored
+func Initialize(m map[string]string) *Cache {
+	c := &Cache{Cache: *representation1.Initialize(nil)}
+	if m2, err := Schema.Migrate(m); err == nil {
+		c.Update(m2)
+	}
+	return c
+}
+
+// Update - apply a version 2 configuration map
+func (c *Cache) Update(m map[string]string) {
+	v1, err := downgrade(m)
+	if err != nil {
+		return
+	}
+	c.Cache.Update(v1)
+}
+
+// Map - export the configuration, including defaults, as a version 2 configuration map
+func (c *Cache) Map() map[string]string {
+	m, _ := upgrade(c.Cache.Map())
+	m[representation.VersionKey] = Fragment
+	return m
+}
+
+// upgrade - convert a version 1 configuration map
+func upgrade(m map[string]string) (map[string]string, error) {
+	v2 := representation.Without(m)
+	representation.Group(v2, ScheduleKey, schedule)
+	representation.Group(v2, TTLPolicyKey, ttlPolicy)
+	return v2, nil
+}
+
+// downgrade - convert a version 2 configuration map to the version 1 keys of the representation
+func downgrade(m map[string]string) (map[string]string, error) {
+	v1 := representation.Without(m, representation.VersionKey)
+	if err := representation.Ungroup(v1, ScheduleKey, schedule); err != nil {
+		return nil, err
+	}
+	if err := representation.Ungroup(v1, TTLPolicyKey, ttlPolicy); err != nil {
+		return nil, err
+	}
+	return v1, nil
+}
+
+func validate(m map[string]string) error {
+	_, err := downgrade(m)
+	return err
+}
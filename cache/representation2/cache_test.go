@@ -0,0 +1,66 @@
+package representation2
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/intermediary/cache/representation1"
+	"github.com/behavioral-ai/intermediary/representation"
+	"maps"
+)
+
+func ExampleInitialize() {
+	c := Initialize(map[string]string{
+		representation.VersionKey:  Fragment,
+		representation1.HostKey:    "www.google.com",
+		ScheduleKey:                "mon:8-16|tue:6-10",
+		TTLPolicyKey:               "redirect:1h|not-found:5m",
+		representation1.TimeoutKey: "750ms",
+	})
+	fmt.Printf("test: Initialize() -> [days:%v] [redirect:%v] [not-found:%v] [host:%v] [timeout:%v]\n",
+		len(c.Days), c.Negative.Redirect, c.Negative.NotFound, c.Host, c.Timeout)
+
+	m := c.Map()
+	fmt.Printf("test: Map() -> [version:%v] [schedule:%v] [ttl-policy:%v] [mon:%v]\n", m[representation.VersionKey], m[ScheduleKey], m[TTLPolicyKey], m[representation1.MondayKey])
+	fmt.Printf("test: Initialize(Map()) -> [equal:%v]\n", maps.Equal(m, Initialize(m).Map()))
+
+	// A map without a version is version 1
+	c = Initialize(map[string]string{representation1.HostKey: "www.google.com", representation1.MondayKey: "8-16"})
+	fmt.Printf("test: Initialize(v1) -> [host:%v] [days:%v]\n", c.Host, len(c.Days))
+
+	//Output:
+	//test: Initialize() -> [days:2] [redirect:1h0m0s] [not-found:5m0s] [host:www.google.com] [timeout:750ms]
+	//test: Map() -> [version:v2] [schedule:mon:8-16|tue:6-10] [ttl-policy:redirect:60m|not-found:5m|server-error:0ms] [mon:]
+	//test: Initialize(Map()) -> [equal:true]
+	//test: Initialize(v1) -> [host:www.google.com] [days:1]
+
+}
+
+func ExampleSchema() {
+	m := map[string]string{
+		representation1.HostKey:           "www.google.com",
+		representation1.SundayKey:         "13-15",
+		representation1.MondayKey:         "8-16",
+		representation1.ServerErrorTTLKey: "5s",
+	}
+	v2, err := Schema.Migrate(m)
+	fmt.Printf("test: Migrate() -> [version:%v] [schedule:%v] [ttl-policy:%v] [mon:%v] [err:%v]\n",
+		v2[representation.VersionKey], v2[ScheduleKey], v2[TTLPolicyKey], v2[representation1.MondayKey], err)
+
+	v1, err := downgrade(v2)
+	fmt.Printf("test: downgrade() -> [equal:%v] [err:%v]\n", maps.Equal(m, v1), err)
+
+	_, err = Schema.Migrate(map[string]string{representation.VersionKey: "v3"})
+	fmt.Printf("test: Migrate(\"v3\") -> [err:%v]\n", err)
+
+	_, err = Schema.Migrate(map[string]string{representation.VersionKey: Fragment, ScheduleKey: "xyz:8-16"})
+	fmt.Printf("test: Migrate() -> [err:%v]\n", err)
+
+	fmt.Printf("test: Fragments() -> %v\n", Schema.Fragments())
+
+	//Output:
+	//test: Migrate() -> [version:v2] [schedule:sun:13-15|mon:8-16] [ttl-policy:server-error:5s] [mon:] [err:<nil>]
+	//test: downgrade() -> [equal:true] [err:<nil>]
+	//test: Migrate("v3") -> [err:invalid version [v3]]
+	//test: Migrate() -> [err:invalid schedule [xyz:8-16]]
+	//test: Fragments() -> [v2 v1]
+
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/core/uri"
+	"github.com/behavioral-ai/intermediary/compression"
+	"github.com/behavioral-ai/intermediary/request"
+	"io"
+	"net/http"
+)
+
+const (
+	VariantQuery  = "x-cache-encoding" // Query parameter keying a stored content-encoding variant
+	contentLength = "Content-Length"
+)
+
+// variantURL - cache URL for a content-encoding variant, an empty encoding is identity
+func variantURL(host string, r *http.Request, encoding string) string {
+	if encoding == "" {
+		encoding = compression.IdentityEncoding
+	}
+	values := r.URL.Query()
+	values.Set(VariantQuery, encoding)
+	return uri.BuildURL(host, r.URL.Path, values)
+}
+
+// lookup - select the variant accepted by the client, the stored compressed variant if accepted,
+// otherwise the identity entry transcoded to the accepted encoding
+func (a *agentT) lookup(r *http.Request, h http.Header) (*http.Response, *messaging.Status) {
+	state := a.state.Load()
+	accept := r.Header.Get(compression.AcceptEncoding)
+	encoding := compression.Negotiate(accept)
+	if encoding != "" && encoding == state.Variant {
+		resp, status := a.do(r.Context(), http.MethodGet, variantURL(state.Host, r, encoding), h, nil)
+		if resp.StatusCode == http.StatusOK || r.Context().Err() != nil {
+			return resp, status
+		}
+		request.Discard(resp)
+	}
+	resp, status := a.do(r.Context(), http.MethodGet, variantURL(state.Host, r, ""), h, nil)
+	if resp.StatusCode != http.StatusOK {
+		return resp, status
+	}
+	from := resp.Header.Get(compression.ContentEncoding)
+	if compression.Accepted(accept, from) && (from != "" || encoding == "") {
+		return resp, status
+	}
+	if !compression.Supported(from) {
+		// Content cannot be transcoded, treat as a miss
+		request.Discard(resp)
+		return httpx.NewResponse(http.StatusNotFound, nil, nil), messaging.NewStatus(http.StatusNotFound, nil)
+	}
+	buf, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err == nil {
+		buf, err = compression.Transcode(buf, from, encoding)
+	}
+	if err != nil {
+		return httpx.NewResponse(http.StatusInternalServerError, nil, nil), messaging.NewStatus(messaging.StatusIOError, err)
+	}
+	if encoding == "" {
+		resp.Header.Del(compression.ContentEncoding)
+	} else {
+		resp.Header.Set(compression.ContentEncoding, encoding)
+	}
+	compression.SetETag(resp.Header, encoding)
+	request.SetBody(resp, buf)
+	return resp, status
+}
+
+// cacheUpdate - normalize the upstream response, storing the identity entry and the compressed variant.
+// Content with an encoding that cannot be decoded is not cached.
+func (a *agentT) cacheUpdate(r *http.Request, resp *http.Response) error {
+	state := a.state.Load()
+	var (
+		buf    []byte
+		err    error
+		status *messaging.Status
+	)
+	buf, err = io.ReadAll(resp.Body)
+	if err != nil {
+		status = messaging.NewStatus(messaging.StatusIOError, err).WithLocation(a.Name())
+		a.service.Message(messaging.NewStatusMessage(status, a.Name()))
+		return err
+	}
+	resp.Body.Close()
+	request.SetBody(resp, buf)
+	encoding := resp.Header.Get(compression.ContentEncoding)
+	if !compression.Supported(encoding) {
+		return nil
+	}
+	h := httpx.CloneHeader(resp.Header)
+	h.Add(httpx.XRequestId, r.Header.Get(httpx.XRequestId))
+	h.Del(compression.ContentEncoding)
+	h.Del(contentLength)
+	// Each stored encoding has a distinct entity tag, the upstream tag is kept for the upstream encoding
+	tag := h.Get(compression.ETag)
+	if encoding != "" && encoding != compression.IdentityEncoding {
+		compression.SetETag(h, compression.IdentityEncoding)
+	}
+	variant := state.Variant
+
+	// cache update
+	go func() {
+		identity, err1 := compression.Decode(buf, encoding)
+		if err1 != nil {
+			a.service.Message(messaging.NewStatusMessage(messaging.NewStatus(messaging.StatusIOError, err1).WithLocation(a.Name()), a.Name()))
+			return
+		}
+		a.put(variantURL(state.Host, r, ""), h, identity)
+		if variant == "" {
+			return
+		}
+		compressed := buf
+		if encoding != variant {
+			compressed, err1 = compression.Encode(identity, variant)
+			if err1 != nil {
+				a.service.Message(messaging.NewStatusMessage(messaging.NewStatus(messaging.StatusIOError, err1).WithLocation(a.Name()), a.Name()))
+				return
+			}
+		}
+		h2 := h.Clone()
+		h2.Set(compression.ContentEncoding, variant)
+		if tag != "" {
+			h2.Set(compression.ETag, tag)
+			if encoding != variant {
+				compression.SetETag(h2, variant)
+			}
+		}
+		a.put(variantURL(state.Host, r, variant), h2, compressed)
+	}()
+	return nil
+}
+
+// put - store a cache entry, the update is independent of the inbound request, which may complete first
+func (a *agentT) put(url string, h http.Header, buf []byte) {
+	_, status := a.do(context.Background(), http.MethodPut, url, h, io.NopCloser(bytes.NewReader(buf)))
+	if status.Err != nil {
+		a.service.Message(messaging.NewStatusMessage(status.WithLocation(a.Name()), a.Name()))
+	}
+}
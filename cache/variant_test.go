@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/core/access2"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/intermediary/cache/representation1"
+	"github.com/behavioral-ai/intermediary/cache/representation2"
+	"github.com/behavioral-ai/intermediary/compression"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	variantContent = strings.Repeat("the quick brown fox jumps over the lazy dog ", 50)
+)
+
+type entry struct {
+	header http.Header
+	body   []byte
+}
+
+// memoryCache - in memory cache host exchange
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+func (c *memoryCache) exchange(r *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch r.Method {
+	case http.MethodGet:
+		if e, ok := c.entries[r.URL.String()]; ok {
+			return httpx.NewResponse(http.StatusOK, e.header.Clone(), e.body), nil
+		}
+		return httpx.NewResponse(http.StatusNotFound, nil, nil), nil
+	case http.MethodPut:
+		buf, _ := io.ReadAll(r.Body)
+		c.entries[r.URL.String()] = entry{header: r.Header.Clone(), body: buf}
+		return httpx.NewResponse(http.StatusOK, nil, nil), nil
+	}
+	return httpx.NewResponse(http.StatusMethodNotAllowed, nil, nil), nil
+}
+
+func (c *memoryCache) keys() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var keys []string
+	for k := range c.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func gzipExchange(r *http.Request) (*http.Response, error) {
+	h := make(http.Header)
+	h.Set("Content-Type", "text/plain")
+	buf, _ := compression.Encode([]byte(variantContent), compression.GzipEncoding)
+	h.Set(compression.ContentEncoding, compression.GzipEncoding)
+	h.Set(compression.Vary, compression.AcceptEncoding)
+	h.Set(compression.ETag, "\"v1\"")
+	return httpx.NewResponse(http.StatusOK, h, buf), nil
+}
+
+func Example_linkVariant() {
+	c := &memoryCache{entries: make(map[string]entry)}
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.HostKey: "localhost:8082",
+		representation1.ModeKey: representation1.ModeOn,
+	}), c.exchange, operationstest.NewService())
+	ex := a.Link(gzipExchange)
+
+	send := func(accept string) {
+		req, _ := http.NewRequest(http.MethodGet, "https://localhost:8081/search?q=golang", nil)
+		if accept != "" {
+			req.Header.Set(compression.AcceptEncoding, accept)
+		}
+		resp, err := ex(req)
+		buf, _ := io.ReadAll(resp.Body)
+		buf, _ = compression.Decode(buf, resp.Header.Get(compression.ContentEncoding))
+		fmt.Printf("test: Link(%v) -> [cached:%v] [encoding:%v] [vary:%v] [etag:%v] [equal:%v] [err:%v]\n", accept, resp.Header.Get(access2.XCached),
+			resp.Header.Get(compression.ContentEncoding), resp.Header.Values(compression.Vary), resp.Header.Get(compression.ETag), bytes.Equal(buf, []byte(variantContent)), err)
+	}
+	send("gzip")
+	time.Sleep(time.Millisecond * 100)
+	fmt.Printf("test: cacheUpdate() -> %v\n", c.keys())
+
+	send("gzip")
+	send("")
+	send("deflate")
+
+	//Output:
+	//test: Link(gzip) -> [cached:] [encoding:gzip] [vary:[Accept-Encoding]] [etag:"v1"] [equal:true] [err:<nil>]
+	//test: cacheUpdate() -> [https://localhost:8082/search?q=golang&x-cache-encoding=gzip https://localhost:8082/search?q=golang&x-cache-encoding=identity]
+	//test: Link(gzip) -> [cached:true] [encoding:gzip] [vary:[Accept-Encoding]] [etag:"v1"] [equal:true] [err:<nil>]
+	//test: Link() -> [cached:true] [encoding:] [vary:[Accept-Encoding]] [etag:W/"v1"] [equal:true] [err:<nil>]
+	//test: Link(deflate) -> [cached:true] [encoding:deflate] [vary:[Accept-Encoding]] [etag:W/"v1-deflate"] [equal:true] [err:<nil>]
+
+}
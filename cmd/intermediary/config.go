@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/module"
+	"github.com/behavioral-ai/intermediary/pipeline"
+	"os"
+	"strings"
+)
+
+const (
+	defaultAddr = ":8080"
+)
+
+// config - server configuration file, agent configuration is keyed by namespace name or alias, with an
+// optional instance suffix, "cache#orders"
+type config struct {
+	Addr   string                       `json:"addr"`
+	Chain  []string                     `json:"chain"`
+	Agents map[string]map[string]string `json:"agents"`
+}
+
+// readConfig - read a configuration file, an empty name returns the default configuration
+func readConfig(name string) (*config, error) {
+	cfg := &config{Addr: defaultAddr}
+	if name == "" {
+		return cfg, nil
+	}
+	buf, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(buf, cfg)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid configuration file [%v]: %v", name, err))
+	}
+	return cfg, nil
+}
+
+// parseChain - parse a comma separated list of agent names
+func parseChain(s string) []string {
+	var chain []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			chain = append(chain, name)
+		}
+	}
+	return chain
+}
+
+// buildChain - compose the pipeline and configure its agents
+func buildChain(cfg *config) (*pipeline.Pipeline, error) {
+	var names []string
+	for _, name := range cfg.Chain {
+		names = append(names, module.NamespaceName(name))
+	}
+	p, err := pipeline.New(names)
+	if err != nil {
+		return nil, err
+	}
+	settings := make(map[string]map[string]string)
+	for name, m := range cfg.Agents {
+		settings[module.NamespaceName(name)] = m
+	}
+	for _, agent := range p.Agents() {
+		if m, ok := settings[agent.Name()]; ok {
+			agent.Message(messaging.NewMapMessage(m))
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func Example_buildChain() {
+	name := filepath.Join(os.TempDir(), "intermediary-example.json")
+	os.WriteFile(name, []byte(`{
+  "addr": ":8081",
+  "chain": ["cache", "routing"],
+  "agents": {
+    "cache": {"host": "localhost:8082"},
+    "routing": {"app-host": "localhost:8083"}
+  }
+}`), 0644)
+	defer os.Remove(name)
+
+	cfg, err := readConfig(name)
+	fmt.Printf("test: readConfig() -> [addr:%v] [chain:%v] [err:%v]\n", cfg.Addr, cfg.Chain, err)
+
+	p, err := buildChain(cfg)
+	fmt.Printf("test: buildChain() -> [agents:%v] [err:%v]\n", len(p.Agents()), err)
+
+	cfg.Chain = parseChain("routing, cache")
+	_, err = buildChain(cfg)
+	fmt.Printf("test: buildChain(%v) -> [err:%v]\n", cfg.Chain, err)
+
+	cfg.Chain = parseChain("cache,unknown")
+	_, err = buildChain(cfg)
+	fmt.Printf("test: buildChain(%v) -> [err:%v]\n", cfg.Chain, err)
+
+	//Output:
+	//test: readConfig() -> [addr::8081] [chain:[cache routing]] [err:<nil>]
+	//test: buildChain() -> [agents:2] [err:<nil>]
+	//test: buildChain([routing cache]) -> [err:last agent is not a terminal exchange [test:resiliency:agent/cache/request/http]]
+	//test: buildChain([cache unknown]) -> [err:agent not found [unknown]]
+
+}
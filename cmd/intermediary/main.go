@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"github.com/behavioral-ai/core/host"
+	"github.com/behavioral-ai/intermediary/loader"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Intermediary server, an http.Handler built from a chain of agents, configured at startup, and drained on
+// SIGTERM or SIGINT. Agent configuration in an agents file, JSON or a YAML subset, is reloaded when the file changes.
+//
+//	intermediary -addr :8080 -config intermediary.json -chain cache,routing -agents agents.yaml
+func main() {
+	var opts options
+
+	flag.StringVar(&opts.addr, "addr", "", "listen address, overrides the configuration file")
+	flag.StringVar(&opts.config, "config", "", "configuration file, JSON")
+	flag.StringVar(&opts.chain, "chain", "", "comma separated agent names or aliases, overrides the configuration file")
+	flag.DurationVar(&opts.drain, "drain", time.Second*30, "maximum time to drain connections on shutdown")
+	flag.StringVar(&opts.agents, "agents", "", "agent configuration file, JSON or a YAML subset of agent names and scalar keys, reloaded on change")
+	flag.DurationVar(&opts.reload, "reload", time.Second*5, "agent configuration file polling interval")
+	flag.Parse()
+
+	if err := run(opts); err != nil {
+		log.Printf("intermediary: %v", err)
+		os.Exit(1)
+	}
+}
+
+type options struct {
+	addr   string
+	config string
+	chain  string
+	drain  time.Duration
+	agents string
+	reload time.Duration
+}
+
+func run(opts options) error {
+	cfg, err := readConfig(opts.config)
+	if err != nil {
+		return err
+	}
+	if opts.addr != "" {
+		cfg.Addr = opts.addr
+	}
+	if opts.chain != "" {
+		cfg.Chain = parseChain(opts.chain)
+	}
+	p, err := buildChain(cfg)
+	if err != nil {
+		return err
+	}
+	if opts.agents != "" {
+		l := loader.New(opts.agents, opts.reload)
+		if err = l.Load(); err != nil {
+			return err
+		}
+		l.Run()
+		defer l.Stop()
+	}
+	p.Startup()
+	srv := &http.Server{Addr: cfg.Addr, Handler: host.NewEndpoint([]any{p.Exchange})}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	errs := make(chan error, 1)
+	go func() {
+		log.Printf("intermediary: listening on %v", cfg.Addr)
+		errs <- srv.ListenAndServe()
+	}()
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		log.Printf("intermediary: shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), opts.drain)
+		defer cancel()
+		err = srv.Shutdown(shutdownCtx)
+	}
+	p.Shutdown()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,242 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	AcceptEncoding   = "Accept-Encoding"
+	ContentEncoding  = "Content-Encoding"
+	GzipEncoding     = "gzip"
+	DeflateEncoding  = "deflate"
+	BrotliEncoding   = "br"
+	IdentityEncoding = "identity"
+	Vary             = "Vary"
+	ETag             = "ETag"
+	weakPrefix       = "W/"
+)
+
+// Encodings - supported encodings in server preference order. Brotli is not available in the standard library,
+// so it is never selected, and brotli encoded content is passed through as is.
+var Encodings = []string{GzipEncoding, DeflateEncoding}
+
+// Supported - determine if an encoding can be encoded and decoded, identity is always supported
+func Supported(encoding string) bool {
+	encoding = normalize(encoding)
+	if encoding == IdentityEncoding {
+		return true
+	}
+	for _, e := range Encodings {
+		if e == encoding {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate - select the supported encoding preferred by an Accept-Encoding header value, an empty string
+// is returned for identity
+func Negotiate(accept string) string {
+	q := parseAccept(accept)
+	best := ""
+	bestQ := 0.0
+	for _, e := range Encodings {
+		v, ok := q[e]
+		if !ok {
+			v, ok = q["*"]
+		}
+		if ok && v > bestQ {
+			best = e
+			bestQ = v
+		}
+	}
+	return best
+}
+
+// Accepted - determine if an Accept-Encoding header value accepts an encoding
+func Accepted(accept, encoding string) bool {
+	encoding = normalize(encoding)
+	q := parseAccept(accept)
+	if encoding == IdentityEncoding {
+		// Identity is acceptable unless explicitly excluded, RFC 9110 12.5.3
+		if v, ok := q[IdentityEncoding]; ok {
+			return v > 0
+		}
+		if v, ok := q["*"]; ok {
+			return v > 0
+		}
+		return true
+	}
+	if v, ok := q[encoding]; ok {
+		return v > 0
+	}
+	v, ok := q["*"]
+	return ok && v > 0
+}
+
+// Encode - encode content
+func Encode(buf []byte, encoding string) ([]byte, error) {
+	var out bytes.Buffer
+	var w io.WriteCloser
+
+	switch normalize(encoding) {
+	case IdentityEncoding:
+		return buf, nil
+	case GzipEncoding:
+		w = gzip.NewWriter(&out)
+	case DeflateEncoding:
+		// HTTP deflate is the zlib format, RFC 9110 8.4.1.2
+		w = zlib.NewWriter(&out)
+	default:
+		return nil, errors.New(fmt.Sprintf("encoding not supported [%v]", encoding))
+	}
+	if _, err := w.Write(buf); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// Decode - decode content
+func Decode(buf []byte, encoding string) ([]byte, error) {
+	var r io.ReadCloser
+
+	switch normalize(encoding) {
+	case IdentityEncoding:
+		return buf, nil
+	case GzipEncoding:
+		zr, err := gzip.NewReader(bytes.NewReader(buf))
+		if err != nil {
+			return nil, err
+		}
+		r = zr
+	case DeflateEncoding:
+		zr, err := zlib.NewReader(bytes.NewReader(buf))
+		if err != nil {
+			return nil, err
+		}
+		r = zr
+	default:
+		return nil, errors.New(fmt.Sprintf("encoding not supported [%v]", encoding))
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// Transcode - convert content from one encoding to another
+func Transcode(buf []byte, from, to string) ([]byte, error) {
+	if normalize(from) == normalize(to) {
+		return buf, nil
+	}
+	identity, err := Decode(buf, from)
+	if err != nil {
+		return nil, err
+	}
+	return Encode(identity, to)
+}
+
+// AddVary - add Accept-Encoding to the Vary header, unless it is already listed, or all request headers vary
+func AddVary(h http.Header) {
+	for _, v := range h.Values(Vary) {
+		for _, name := range strings.Split(v, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" || strings.EqualFold(name, AcceptEncoding) {
+				return
+			}
+		}
+	}
+	h.Add(Vary, AcceptEncoding)
+}
+
+// SetETag - update the entity tag for content converted to an encoding. The opaque tag is suffixed with the
+// encoding, so that each encoding of a representation has a distinct tag. Content decoded to identity has a
+// weak tag, as the tag of the unencoded representation is not known.
+func SetETag(h http.Header, encoding string) {
+	tag := h.Get(ETag)
+	if tag == "" {
+		return
+	}
+	weak := strings.HasPrefix(tag, weakPrefix)
+	opaque := strings.TrimPrefix(tag, weakPrefix)
+	if len(opaque) < 2 || opaque[0] != '"' || opaque[len(opaque)-1] != '"' {
+		h.Del(ETag)
+		return
+	}
+	encoding = normalize(encoding)
+	if encoding == IdentityEncoding {
+		h.Set(ETag, weakPrefix+opaque)
+		return
+	}
+	tag = opaque[:len(opaque)-1] + "-" + encoding + "\""
+	if weak {
+		tag = weakPrefix + tag
+	}
+	h.Set(ETag, tag)
+}
+
+// Allowed - determine if a content type matches an allowlist, entries may use a subtype wildcard, "text/*"
+func Allowed(contentType string, types []string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == mt {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok && strings.HasPrefix(mt, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize - an empty encoding is identity, and x-gzip is an alias for gzip
+func normalize(encoding string) string {
+	encoding = strings.ToLower(strings.TrimSpace(encoding))
+	switch encoding {
+	case "":
+		return IdentityEncoding
+	case "x-gzip":
+		return GzipEncoding
+	}
+	return encoding
+}
+
+// parseAccept - parse an Accept-Encoding header value into encoding quality values
+func parseAccept(accept string) map[string]float64 {
+	q := make(map[string]float64)
+	for _, item := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(item, ";")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if name != "*" {
+			name = normalize(name)
+		}
+		v := 1.0
+		for _, p := range strings.Split(params, ";") {
+			k, s, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if ok && strings.TrimSpace(k) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+					v = f
+				}
+			}
+		}
+		q[name] = v
+	}
+	return q
+}
@@ -0,0 +1,120 @@
+package compression
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleNegotiate() {
+	fmt.Printf("test: Negotiate(\"\") -> [%v]\n", Negotiate(""))
+	fmt.Printf("test: Negotiate(\"gzip, deflate, br\") -> [%v]\n", Negotiate("gzip, deflate, br"))
+	fmt.Printf("test: Negotiate(\"br, deflate\") -> [%v]\n", Negotiate("br, deflate"))
+	fmt.Printf("test: Negotiate(\"gzip;q=0.5, deflate;q=0.8\") -> [%v]\n", Negotiate("gzip;q=0.5, deflate;q=0.8"))
+	fmt.Printf("test: Negotiate(\"*;q=0.1, gzip;q=0\") -> [%v]\n", Negotiate("*;q=0.1, gzip;q=0"))
+
+	//Output:
+	//test: Negotiate("") -> []
+	//test: Negotiate("gzip, deflate, br") -> [gzip]
+	//test: Negotiate("br, deflate") -> [deflate]
+	//test: Negotiate("gzip;q=0.5, deflate;q=0.8") -> [deflate]
+	//test: Negotiate("*;q=0.1, gzip;q=0") -> [deflate]
+
+}
+
+func ExampleAccepted() {
+	fmt.Printf("test: Accepted(\"\",gzip) -> [%v]\n", Accepted("", GzipEncoding))
+	fmt.Printf("test: Accepted(\"\",identity) -> [%v]\n", Accepted("", IdentityEncoding))
+	fmt.Printf("test: Accepted(\"x-gzip\",gzip) -> [%v]\n", Accepted("x-gzip", GzipEncoding))
+	fmt.Printf("test: Accepted(\"gzip;q=0\",gzip) -> [%v]\n", Accepted("gzip;q=0", GzipEncoding))
+	fmt.Printf("test: Accepted(\"*;q=0\",identity) -> [%v]\n", Accepted("*;q=0", IdentityEncoding))
+
+	//Output:
+	//test: Accepted("",gzip) -> [false]
+	//test: Accepted("",identity) -> [true]
+	//test: Accepted("x-gzip",gzip) -> [true]
+	//test: Accepted("gzip;q=0",gzip) -> [false]
+	//test: Accepted("*;q=0",identity) -> [false]
+
+}
+
+func ExampleTranscode() {
+	content := []byte(strings.Repeat("the quick brown fox jumps over the lazy dog ", 20))
+
+	buf, err := Encode(content, GzipEncoding)
+	fmt.Printf("test: Encode(gzip) -> [smaller:%v] [err:%v]\n", len(buf) < len(content), err)
+
+	buf, err = Transcode(buf, GzipEncoding, DeflateEncoding)
+	fmt.Printf("test: Transcode(gzip,deflate) -> [smaller:%v] [err:%v]\n", len(buf) < len(content), err)
+
+	buf, err = Decode(buf, DeflateEncoding)
+	fmt.Printf("test: Decode(deflate) -> [equal:%v] [err:%v]\n", string(buf) == string(content), err)
+
+	_, err = Encode(content, BrotliEncoding)
+	fmt.Printf("test: Encode(br) -> [err:%v]\n", err)
+
+	//Output:
+	//test: Encode(gzip) -> [smaller:true] [err:<nil>]
+	//test: Transcode(gzip,deflate) -> [smaller:true] [err:<nil>]
+	//test: Decode(deflate) -> [equal:true] [err:<nil>]
+	//test: Encode(br) -> [err:encoding not supported [br]]
+
+}
+
+func ExampleDecode_deflate() {
+	// zlib framed "hello, world"
+	fixture := []byte{0x78, 0x9c, 0xcb, 0x48, 0xcd, 0xc9, 0xc9, 0xd7, 0x51, 0x28, 0xcf, 0x2f, 0xca, 0x49, 0x01, 0x00, 0x1d, 0x54, 0x04, 0x89}
+
+	buf, err := Decode(fixture, DeflateEncoding)
+	fmt.Printf("test: Decode(deflate) -> [%v] [err:%v]\n", string(buf), err)
+
+	buf, err = Encode(buf, DeflateEncoding)
+	fmt.Printf("test: Encode(deflate) -> [zlib:%v] [err:%v]\n", len(buf) > 2 && buf[0] == 0x78 && (uint16(buf[0])<<8|uint16(buf[1]))%31 == 0, err)
+
+	buf, err = Decode(buf, DeflateEncoding)
+	fmt.Printf("test: Decode(deflate) -> [%v] [err:%v]\n", string(buf), err)
+
+	_, err = Decode(fixture[2:], DeflateEncoding)
+	fmt.Printf("test: Decode(raw deflate) -> [err:%v]\n", err)
+
+	//Output:
+	//test: Decode(deflate) -> [hello, world] [err:<nil>]
+	//test: Encode(deflate) -> [zlib:true] [err:<nil>]
+	//test: Decode(deflate) -> [hello, world] [err:<nil>]
+	//test: Decode(raw deflate) -> [err:zlib: invalid header]
+
+}
+
+func ExampleSetETag() {
+	for _, tag := range []string{"\"v1\"", "W/\"v1\"", "v1"} {
+		h := make(http.Header)
+		h.Set(ETag, tag)
+		SetETag(h, GzipEncoding)
+		gzip := h.Get(ETag)
+		h.Set(ETag, tag)
+		SetETag(h, IdentityEncoding)
+		fmt.Printf("test: SetETag(%v) -> [gzip:%v] [identity:%v]\n", tag, gzip, h.Get(ETag))
+	}
+
+	//Output:
+	//test: SetETag("v1") -> [gzip:"v1-gzip"] [identity:W/"v1"]
+	//test: SetETag(W/"v1") -> [gzip:W/"v1-gzip"] [identity:W/"v1"]
+	//test: SetETag(v1) -> [gzip:] [identity:]
+
+}
+
+func ExampleAllowed() {
+	types := []string{"text/*", "application/json"}
+
+	fmt.Printf("test: Allowed(text/html) -> [%v]\n", Allowed("text/html; charset=utf-8", types))
+	fmt.Printf("test: Allowed(application/json) -> [%v]\n", Allowed("application/json", types))
+	fmt.Printf("test: Allowed(image/png) -> [%v]\n", Allowed("image/png", types))
+	fmt.Printf("test: Allowed(\"\") -> [%v]\n", Allowed("", types))
+
+	//Output:
+	//test: Allowed(text/html) -> [true]
+	//test: Allowed(application/json) -> [true]
+	//test: Allowed(image/png) -> [false]
+	//test: Allowed("") -> [false]
+
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/behavioral-ai/core/messaging"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	QueryEvent = "resiliency:event/config/query"
+
+	listSeparator  = "|"
+	defaultTimeout = time.Second
+)
+
+// NewQueryMessage - create a query for the effective agent configuration, the agent replies with a map message
+// containing all keys, including defaults
+func NewQueryMessage(reply messaging.Handler) *messaging.Message {
+	m := messaging.NewMessage(messaging.ChannelControl, QueryEvent)
+	m.Reply = reply
+	return m
+}
+
+// Query - effective configuration of an agent, a zero timeout uses the default
+func Query(agent messaging.Agent, timeout time.Duration) (map[string]string, *messaging.Status) {
+	return request(agent, timeout, NewQueryMessage)
+}
+
+// request - send a message, and wait for a map reply
+func request(agent messaging.Agent, timeout time.Duration, newMessage func(reply messaging.Handler) *messaging.Message) (map[string]string, *messaging.Status) {
+	if agent == nil {
+		return nil, messaging.NewStatus(messaging.StatusInvalidArgument, errors.New("agent is nil"))
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	reply := make(chan *messaging.Message, 1)
+	agent.Message(newMessage(func(m *messaging.Message) {
+		select {
+		case reply <- m:
+		default:
+		}
+	}))
+	select {
+	case m := <-reply:
+		return messaging.MapContent(m)
+	case <-time.After(timeout):
+		return nil, messaging.NewStatus(messaging.StatusNotProvided, errors.New(fmt.Sprintf("query timeout [%v]", agent.Name())))
+	}
+}
+
+// Snapshot - effective configuration of agents by name, agents that do not reply are omitted, and reported
+// in the status
+func Snapshot(timeout time.Duration, agents ...messaging.Agent) (map[string]map[string]string, *messaging.Status) {
+	snapshot := make(map[string]map[string]string)
+	var names []string
+	for _, agent := range agents {
+		m, status := Query(agent, timeout)
+		if !status.OK() {
+			if agent != nil {
+				names = append(names, agent.Name())
+			}
+			continue
+		}
+		snapshot[agent.Name()] = m
+	}
+	if len(names) > 0 {
+		return snapshot, messaging.NewStatus(messaging.StatusNotProvided, errors.New(fmt.Sprintf("agents did not reply [%v]", strings.Join(names, listSeparator))))
+	}
+	return snapshot, messaging.StatusOK()
+}
+
+// Reply - reply to a query with the configuration map
+func Reply(m *messaging.Message, cfg map[string]string) {
+	if m == nil || m.Reply == nil {
+		return
+	}
+	m.Reply(messaging.NewMapMessage(cfg))
+}
+
+// FormatDuration - format a duration as the largest whole unit of minutes, seconds, or milliseconds, so that
+// parsing the result returns the same duration at millisecond resolution
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "0ms"
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%vm", int64(d/time.Minute))
+	case d%time.Second == 0:
+		return fmt.Sprintf("%vs", int64(d/time.Second))
+	}
+	return fmt.Sprintf("%vms", d.Milliseconds())
+}
+
+// FormatList - format "item|item"
+func FormatList(items []string) string {
+	return strings.Join(items, listSeparator)
+}
+
+// FormatBool - format "true" or "false"
+func FormatBool(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+// FormatFloat - format with the fewest digits that parse to the same value
+func FormatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/core/messaging"
+	"time"
+)
+
+type agent struct {
+	name string
+	cfg  map[string]string
+}
+
+func (a *agent) Name() string { return a.name }
+func (a *agent) Message(m *messaging.Message) {
+	if m.Name == QueryEvent && a.cfg != nil {
+		go Reply(m, a.cfg)
+	}
+}
+
+func ExampleFormatDuration() {
+	for _, d := range []time.Duration{0, time.Millisecond * 750, time.Millisecond * 1500, time.Second * 30, time.Minute * 90} {
+		fmt.Printf("test: FormatDuration(%v) -> %v\n", d, FormatDuration(d))
+	}
+
+	//Output:
+	//test: FormatDuration(0s) -> 0ms
+	//test: FormatDuration(750ms) -> 750ms
+	//test: FormatDuration(1.5s) -> 1500ms
+	//test: FormatDuration(30s) -> 30s
+	//test: FormatDuration(1h30m0s) -> 90m
+
+}
+
+func ExampleQuery() {
+	a := &agent{name: "test:agent/one", cfg: map[string]string{"timeout": "750ms"}}
+	m, status := Query(a, 0)
+	fmt.Printf("test: Query() -> %v [status:%v]\n", m, status)
+
+	_, status = Query(&agent{name: "test:agent/two"}, time.Millisecond*10)
+	fmt.Printf("test: Query() -> [status:%v]\n", status)
+
+	snapshot, status := Snapshot(time.Millisecond*10, a, &agent{name: "test:agent/two"})
+	fmt.Printf("test: Snapshot() -> %v [status:%v]\n", snapshot, status)
+
+	//Output:
+	//test: Query() -> map[timeout:750ms] [status:OK]
+	//test: Query() -> [status:96 [err:query timeout [test:agent/two]]]
+	//test: Snapshot() -> map[test:agent/one:map[timeout:750ms]] [status:96 [err:agents did not reply [test:agent/two]]]
+
+}
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/behavioral-ai/core/messaging"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	HistoryEvent  = "resiliency:event/config/history"
+	RollbackEvent = "resiliency:event/config/rollback"
+
+	TargetKey = "target" // RollbackEvent key, the history version to restore, empty for the previous version
+
+	SourceInitial  = "initial"
+	SourceResource = "resource"
+	SourceRollback = "rollback"
+	SourceUnknown  = "unknown"
+
+	DefaultHistorySize = 16
+)
+
+// Change - previous and current value of a key, an empty value for a key that was not configured
+type Change struct {
+	Previous string `json:"previous"`
+	Current  string `json:"current"`
+}
+
+// Entry - applied configuration
+type Entry struct {
+	Version int               `json:"version"`
+	Time    time.Time         `json:"time"`
+	Source  string            `json:"source"`
+	Changes map[string]Change `json:"changes"`
+	Config  map[string]string `json:"config"` // Effective configuration after the change
+}
+
+// History - bounded history of applied configurations, oldest entries are discarded
+type History struct {
+	mu      sync.Mutex
+	size    int
+	version int
+	entries []Entry
+}
+
+// NewHistory - create a history with the initial configuration as the first version
+func NewHistory(size int, initial map[string]string) *History {
+	h := new(History)
+	h.size = size
+	if h.size <= 0 {
+		h.size = DefaultHistorySize
+	}
+	h.add(SourceInitial, initial)
+	return h
+}
+
+// Add - record the effective configuration, false if there are no changes from the current version
+func (h *History) Add(source string, cfg map[string]string) (Entry, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if source == "" {
+		source = SourceUnknown
+	}
+	return h.add(source, cfg)
+}
+
+func (h *History) add(source string, cfg map[string]string) (Entry, bool) {
+	var prev map[string]string
+	if n := len(h.entries); n > 0 {
+		prev = h.entries[n-1].Config
+	}
+	changes := Diff(prev, cfg)
+	if len(h.entries) > 0 && len(changes) == 0 {
+		return Entry{}, false
+	}
+	h.version++
+	e := Entry{Version: h.version, Time: time.Now().UTC(), Source: source, Changes: changes, Config: maps.Clone(cfg)}
+	h.entries = append(h.entries, e)
+	if len(h.entries) > h.size {
+		h.entries = slices.Delete(h.entries, 0, len(h.entries)-h.size)
+	}
+	return e, true
+}
+
+// Entries - history, oldest first
+func (h *History) Entries() []Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.entries)
+}
+
+// Current - entry of the current version
+func (h *History) Current() (Entry, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if n := len(h.entries); n > 0 {
+		return h.entries[n-1], true
+	}
+	return Entry{}, false
+}
+
+// Lookup - entry for a version, zero for the version before the current version
+func (h *History) Lookup(version int) (Entry, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if version == 0 {
+		if n := len(h.entries); n > 1 {
+			return h.entries[n-2], true
+		}
+		return Entry{}, false
+	}
+	for _, e := range h.entries {
+		if e.Version == version {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Target - entry to restore for a RollbackEvent
+func (h *History) Target(m *messaging.Message) (Entry, *messaging.Status) {
+	version := 0
+	if cfg, status := messaging.MapContent(m); status.OK() && cfg[TargetKey] != "" {
+		v, err := strconv.Atoi(cfg[TargetKey])
+		if err != nil || v <= 0 {
+			return Entry{}, messaging.NewStatus(messaging.StatusInvalidArgument, errors.New(fmt.Sprintf("invalid version [%v]", cfg[TargetKey])))
+		}
+		version = v
+	}
+	e, ok := h.Lookup(version)
+	if !ok {
+		if version == 0 {
+			return Entry{}, messaging.NewStatus(messaging.StatusNotProvided, errors.New("no previous version"))
+		}
+		return Entry{}, messaging.NewStatus(messaging.StatusNotProvided, errors.New(fmt.Sprintf("version not found [%v]", version)))
+	}
+	return e, messaging.StatusOK()
+}
+
+// RollbackSource - source of a RollbackEvent, the sender if set
+func RollbackSource(m *messaging.Message) string {
+	if from := m.From(); from != "" {
+		return from
+	}
+	return SourceRollback
+}
+
+// Diff - changed keys
+func Diff(prev, curr map[string]string) map[string]Change {
+	changes := make(map[string]Change)
+	for k, v := range curr {
+		if p, ok := prev[k]; !ok || p != v {
+			changes[k] = Change{Previous: p, Current: v}
+		}
+	}
+	for k, p := range prev {
+		if _, ok := curr[k]; !ok {
+			changes[k] = Change{Previous: p}
+		}
+	}
+	return changes
+}
+
+// NewHistoryMessage - create a query for the configuration history, the agent replies with a map message
+// of versions to JSON encoded entries
+func NewHistoryMessage(reply messaging.Handler) *messaging.Message {
+	m := messaging.NewMessage(messaging.ChannelControl, HistoryEvent)
+	m.Reply = reply
+	return m
+}
+
+// NewRollbackMessage - create a rollback to a version, zero for the previous version
+func NewRollbackMessage(version int, reply messaging.Handler) *messaging.Message {
+	cfg := make(map[string]string)
+	if version > 0 {
+		cfg[TargetKey] = strconv.Itoa(version)
+	}
+	m := messaging.NewMapMessage(cfg)
+	m.Name = RollbackEvent
+	m.Reply = reply
+	return m
+}
+
+// ReplyHistory - reply to a history query
+func ReplyHistory(m *messaging.Message, h *History) {
+	if m == nil || m.Reply == nil || h == nil {
+		return
+	}
+	cfg := make(map[string]string)
+	for _, e := range h.Entries() {
+		buf, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		cfg[strconv.Itoa(e.Version)] = string(buf)
+	}
+	m.Reply(messaging.NewMapMessage(cfg))
+}
+
+// QueryHistory - configuration history of an agent, oldest first, a zero timeout uses the default
+func QueryHistory(agent messaging.Agent, timeout time.Duration) ([]Entry, *messaging.Status) {
+	cfg, status := request(agent, timeout, NewHistoryMessage)
+	if !status.OK() {
+		return nil, status
+	}
+	var entries []Entry
+	for _, s := range cfg {
+		var e Entry
+		if err := json.Unmarshal([]byte(s), &e); err != nil {
+			return nil, messaging.NewStatus(messaging.StatusInvalidArgument, err)
+		}
+		entries = append(entries, e)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int { return a.Version - b.Version })
+	return entries, messaging.StatusOK()
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/core/messaging"
+)
+
+func ExampleHistory() {
+	h := NewHistory(3, map[string]string{"timeout": "750ms", "host": "localhost:8081"})
+	e, ok := h.Add("", map[string]string{"timeout": "1500ms", "host": "localhost:8081"})
+	fmt.Printf("test: Add() -> [version:%v] [source:%v] [changes:%v] [ok:%v]\n", e.Version, e.Source, e.Changes, ok)
+
+	_, ok = h.Add("loader", map[string]string{"timeout": "1500ms", "host": "localhost:8081"})
+	fmt.Printf("test: Add() -> [ok:%v]\n", ok)
+
+	e, ok = h.Add("loader", map[string]string{"timeout": "1500ms"})
+	fmt.Printf("test: Add() -> [version:%v] [source:%v] [changes:%v] [ok:%v]\n", e.Version, e.Source, e.Changes, ok)
+
+	e, ok = h.Lookup(0)
+	fmt.Printf("test: Lookup(0) -> [version:%v] [config:%v] [ok:%v]\n", e.Version, e.Config, ok)
+
+	h.Add("loader", map[string]string{"timeout": "5s"})
+	var versions []int
+	for _, e = range h.Entries() {
+		versions = append(versions, e.Version)
+	}
+	_, ok = h.Lookup(1)
+	fmt.Printf("test: Entries() -> %v [lookup(1):%v]\n", versions, ok)
+
+	//Output:
+	//test: Add() -> [version:2] [source:unknown] [changes:map[timeout:{750ms 1500ms}]] [ok:true]
+	//test: Add() -> [ok:false]
+	//test: Add() -> [version:3] [source:loader] [changes:map[host:{localhost:8081 }]] [ok:true]
+	//test: Lookup(0) -> [version:2] [config:map[host:localhost:8081 timeout:1500ms]] [ok:true]
+	//test: Entries() -> [2 3 4] [lookup(1):false]
+
+}
+
+func ExampleHistory_Target() {
+	h := NewHistory(0, map[string]string{"timeout": "750ms"})
+	_, status := h.Target(NewRollbackMessage(0, nil))
+	fmt.Printf("test: Target(0) -> [status:%v]\n", status)
+
+	h.Add("loader", map[string]string{"timeout": "1500ms"})
+	e, status := h.Target(NewRollbackMessage(0, nil))
+	fmt.Printf("test: Target(0) -> [version:%v] [config:%v] [status:%v]\n", e.Version, e.Config, status)
+
+	_, status = h.Target(NewRollbackMessage(5, nil))
+	fmt.Printf("test: Target(5) -> [status:%v]\n", status)
+
+	m := messaging.NewMapMessage(map[string]string{TargetKey: "x"})
+	m.Name = RollbackEvent
+	_, status = h.Target(m)
+	fmt.Printf("test: Target(x) -> [status:%v]\n", status)
+
+	//Output:
+	//test: Target(0) -> [status:96 [err:no previous version]]
+	//test: Target(0) -> [version:1] [config:map[timeout:750ms]] [status:OK]
+	//test: Target(5) -> [status:96 [err:version not found [5]]]
+	//test: Target(x) -> [status:3 [err:invalid version [x]]]
+
+}
+
+func ExampleQueryHistory() {
+	h := NewHistory(0, map[string]string{"timeout": "750ms"})
+	h.Add("loader", map[string]string{"timeout": "1500ms"})
+	a := &historyAgent{name: "test:agent/one", history: h}
+
+	entries, status := QueryHistory(a, 0)
+	for _, e := range entries {
+		fmt.Printf("test: QueryHistory() -> [version:%v] [source:%v] [changes:%v]\n", e.Version, e.Source, e.Changes)
+	}
+	fmt.Printf("test: QueryHistory() -> [status:%v]\n", status)
+
+	//Output:
+	//test: QueryHistory() -> [version:1] [source:initial] [changes:map[timeout:{ 750ms}]]
+	//test: QueryHistory() -> [version:2] [source:loader] [changes:map[timeout:{750ms 1500ms}]]
+	//test: QueryHistory() -> [status:OK]
+
+}
+
+type historyAgent struct {
+	name    string
+	history *History
+}
+
+func (a *historyAgent) Name() string { return a.name }
+func (a *historyAgent) Message(m *messaging.Message) {
+	if m.Name == HistoryEvent {
+		ReplyHistory(m, a.history)
+	}
+}
@@ -0,0 +1,71 @@
+package latency
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultSize    = 1000
+	MinimumSamples = 20 // Samples required before a percentile is reported
+)
+
+// Histogram - rolling window of observed latencies, the window is also kept in latency order so that a
+// percentile does not need a sort
+type Histogram struct {
+	mu      sync.Mutex
+	samples []time.Duration // Arrival order
+	sorted  []time.Duration // Latency order
+	next    int
+	full    bool
+}
+
+// NewHistogram - create a histogram with a window of the given size
+func NewHistogram(size int) *Histogram {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	h := new(Histogram)
+	h.samples = make([]time.Duration, size)
+	h.sorted = make([]time.Duration, 0, size)
+	return h
+}
+
+// Observe - add a latency, replacing the oldest when the window is full
+func (h *Histogram) Observe(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.full {
+		i, _ := slices.BinarySearch(h.sorted, h.samples[h.next])
+		h.sorted = slices.Delete(h.sorted, i, i+1)
+	}
+	i, _ := slices.BinarySearch(h.sorted, d)
+	h.sorted = slices.Insert(h.sorted, i, d)
+	h.samples[h.next] = d
+	h.next++
+	if h.next == len(h.samples) {
+		h.next = 0
+		h.full = true
+	}
+}
+
+// Count - number of samples in the window
+func (h *Histogram) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.sorted)
+}
+
+// Percentile - latency at the percentile, 0-100, false if there are too few samples
+func (h *Histogram) Percentile(p float64) (time.Duration, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := len(h.sorted)
+	if n < MinimumSamples {
+		return 0, false
+	}
+	p = min(max(p, 0), 100)
+	i := int(p/100*float64(n)+0.5) - 1
+	return h.sorted[min(max(i, 0), n-1)], true
+}
@@ -0,0 +1,33 @@
+package latency
+
+import (
+	"fmt"
+	"time"
+)
+
+func ExampleNewHistogram() {
+	h := NewHistogram(100)
+	_, ok := h.Percentile(95)
+	fmt.Printf("test: Percentile(95) -> [count:%v] [ok:%v]\n", h.Count(), ok)
+
+	for i := 1; i <= 100; i++ {
+		h.Observe(time.Millisecond * time.Duration(i))
+	}
+	p50, _ := h.Percentile(50)
+	p95, _ := h.Percentile(95)
+	p100, ok := h.Percentile(100)
+	fmt.Printf("test: Percentile() -> [count:%v] [p50:%v] [p95:%v] [p100:%v] [ok:%v]\n", h.Count(), p50, p95, p100, ok)
+
+	// Window is full, oldest samples are replaced
+	for i := 0; i < 50; i++ {
+		h.Observe(time.Second)
+	}
+	p50, _ = h.Percentile(50)
+	fmt.Printf("test: Percentile() -> [count:%v] [p50:%v]\n", h.Count(), p50)
+
+	//Output:
+	//test: Percentile(95) -> [count:0] [ok:false]
+	//test: Percentile() -> [count:100] [p50:50ms] [p95:95ms] [p100:100ms] [ok:true]
+	//test: Percentile() -> [count:100] [p50:100ms]
+
+}
@@ -0,0 +1,139 @@
+package latency
+
+import (
+	"github.com/behavioral-ai/core/fmtx"
+	"github.com/behavioral-ai/intermediary/config"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	TimeoutModeKey       = "timeout-mode"
+	TimeoutPercentileKey = "timeout-percentile"
+	TimeoutFactorKey     = "timeout-factor"
+	TimeoutMinKey        = "timeout-min"
+	TimeoutMaxKey        = "timeout-max"
+
+	ModeStatic   = "static"   // Configured timeout
+	ModeAdaptive = "adaptive" // Percentile of observed latency multiplied by a factor
+
+	defaultPercentile = 99
+	defaultFactor     = 2.0
+	defaultMin        = time.Millisecond * 100
+	defaultMax        = time.Second * 10
+)
+
+// Timeout - adaptive timeout configuration
+type Timeout struct {
+	Mode       string
+	Percentile float64
+	Factor     float64
+	Min        time.Duration
+	Max        time.Duration
+}
+
+// NewTimeout - static timeout with adaptive defaults
+func NewTimeout() Timeout {
+	return Timeout{Mode: ModeStatic, Percentile: defaultPercentile, Factor: defaultFactor, Min: defaultMin, Max: defaultMax}
+}
+
+// Duration - timeout for an upstream, the static timeout is used until there are enough observations
+func (t Timeout) Duration(h *Histogram, static time.Duration) time.Duration {
+	if t.Mode != ModeAdaptive || h == nil {
+		return static
+	}
+	d, ok := h.Percentile(t.Percentile)
+	if !ok {
+		return static
+	}
+	d = time.Duration(float64(d) * t.Factor)
+	return min(max(d, t.Min), t.Max)
+}
+
+// ParseTimeout - update from a configuration map
+func ParseTimeout(t *Timeout, m map[string]string) {
+	if t == nil || m == nil {
+		return
+	}
+	s := m[TimeoutModeKey]
+	if s == ModeStatic || s == ModeAdaptive {
+		t.Mode = s
+	}
+	s = m[TimeoutPercentileKey]
+	if s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f <= 0 || f > 100 {
+			return
+		}
+		t.Percentile = f
+	}
+	s = m[TimeoutFactorKey]
+	if s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f <= 0 {
+			return
+		}
+		t.Factor = f
+	}
+	s = m[TimeoutMinKey]
+	if s != "" {
+		dur, err := fmtx.ParseDuration(s)
+		if err != nil {
+			return
+		}
+		t.Min = dur
+	}
+	s = m[TimeoutMaxKey]
+	if s != "" {
+		dur, err := fmtx.ParseDuration(s)
+		if err != nil {
+			return
+		}
+		t.Max = dur
+	}
+}
+
+// FormatTimeout - add to a configuration map, the inverse of ParseTimeout
+func FormatTimeout(t Timeout, m map[string]string) {
+	if m == nil {
+		return
+	}
+	m[TimeoutModeKey] = t.Mode
+	m[TimeoutPercentileKey] = config.FormatFloat(t.Percentile)
+	m[TimeoutFactorKey] = config.FormatFloat(t.Factor)
+	m[TimeoutMinKey] = config.FormatDuration(t.Min)
+	m[TimeoutMaxKey] = config.FormatDuration(t.Max)
+}
+
+// Tracker - latency histograms by upstream host
+type Tracker struct {
+	mu   sync.Mutex
+	size int
+	m    map[string]*Histogram
+}
+
+// NewTracker - create a tracker with histograms of the given size
+func NewTracker(size int) *Tracker {
+	t := new(Tracker)
+	t.size = size
+	t.m = make(map[string]*Histogram)
+	return t
+}
+
+// Histogram - histogram for an upstream, created on first use
+func (t *Tracker) Histogram(upstream string) *Histogram {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h, ok := t.m[upstream]
+	if !ok {
+		h = NewHistogram(t.size)
+		t.m[upstream] = h
+	}
+	return h
+}
+
+// Observe - add an upstream latency
+func (t *Tracker) Observe(upstream string, d time.Duration) {
+	t.Histogram(upstream).Observe(d)
+}
@@ -0,0 +1,37 @@
+package latency
+
+import (
+	"fmt"
+	"time"
+)
+
+func ExampleTimeout_Duration() {
+	static := time.Millisecond * 2500
+	t := NewTimeout()
+	tracker := NewTracker(100)
+	for i := 1; i <= 100; i++ {
+		tracker.Observe("www.google.com", time.Millisecond*time.Duration(i))
+	}
+	h := tracker.Histogram("www.google.com")
+	fmt.Printf("test: Duration() -> [mode:%v] [timeout:%v]\n", t.Mode, t.Duration(h, static))
+
+	ParseTimeout(&t, map[string]string{TimeoutModeKey: ModeAdaptive, TimeoutPercentileKey: "95", TimeoutFactorKey: "1.5"})
+	fmt.Printf("test: Duration() -> [mode:%v] [timeout:%v]\n", t.Mode, t.Duration(h, static))
+
+	ParseTimeout(&t, map[string]string{TimeoutMinKey: "500ms"})
+	fmt.Printf("test: Duration() -> [min:%v] [timeout:%v]\n", t.Min, t.Duration(h, static))
+
+	ParseTimeout(&t, map[string]string{TimeoutMinKey: "10ms", TimeoutMaxKey: "100ms"})
+	fmt.Printf("test: Duration() -> [max:%v] [timeout:%v]\n", t.Max, t.Duration(h, static))
+
+	// Not enough observations
+	fmt.Printf("test: Duration() -> [count:%v] [timeout:%v]\n", tracker.Histogram("localhost").Count(), t.Duration(tracker.Histogram("localhost"), static))
+
+	//Output:
+	//test: Duration() -> [mode:static] [timeout:2.5s]
+	//test: Duration() -> [mode:adaptive] [timeout:142.5ms]
+	//test: Duration() -> [min:500ms] [timeout:500ms]
+	//test: Duration() -> [max:100ms] [timeout:100ms]
+	//test: Duration() -> [count:0] [timeout:2.5s]
+
+}
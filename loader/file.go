@@ -0,0 +1,125 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Config - agent configuration keyed by agent namespace name
+type Config map[string]map[string]string
+
+// Parse - parse a configuration file, a YAML subset if the file has a .yaml or .yml extension, otherwise JSON
+func Parse(name string, buf []byte) (Config, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		return parseYAML(buf)
+	}
+	cfg := make(Config)
+	err := json.Unmarshal(buf, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// parseYAML - parse a YAML subset, a mapping of agent names to a mapping of scalar keys and values. Agent names
+// are unindented and end with a colon, and keys are indented. Comments start with # at the beginning of a line,
+// or after whitespace. Values are plain or quoted scalars, and other YAML, such as sequences, flow collections,
+// block scalars, anchors, and nested mappings, is rejected.
+//
+//	test:resiliency:agent/cache/request/http: # orders upstream
+//	  host: localhost:8082
+//	  timeout: 750ms
+func parseYAML(buf []byte) (Config, error) {
+	cfg := make(Config)
+	var agent map[string]string
+	indent := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	line := 0
+	for scanner.Scan() {
+		line++
+		s := strings.TrimRight(stripComment(scanner.Text()), " \t\r")
+		trimmed := strings.TrimSpace(s)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if s[0] != ' ' && s[0] != '\t' {
+			name, ok := strings.CutSuffix(s, ":")
+			if !ok {
+				return nil, errors.New(fmt.Sprintf("invalid agent name, line %v", line))
+			}
+			agent = make(map[string]string)
+			cfg[unquote(name)] = agent
+			indent = 0
+			continue
+		}
+		if agent == nil {
+			return nil, errors.New(fmt.Sprintf("key without an agent name, line %v", line))
+		}
+		if n := len(s) - len(strings.TrimLeft(s, " \t")); indent == 0 {
+			indent = n
+		} else if n != indent {
+			return nil, errors.New(fmt.Sprintf("unsupported YAML, nested mappings are not supported, line %v", line))
+		}
+		k, v, ok := strings.Cut(trimmed, ":")
+		if !ok || strings.HasPrefix(trimmed, "-") {
+			return nil, errors.New(fmt.Sprintf("invalid key, line %v", line))
+		}
+		if v = strings.TrimSpace(v); v != "" && strings.ContainsAny(v[:1], "[{|>&*!") {
+			return nil, errors.New(fmt.Sprintf("unsupported YAML, values must be scalars, line %v", line))
+		}
+		agent[unquote(k)] = unquote(v)
+	}
+	return cfg, scanner.Err()
+}
+
+// stripComment - remove a comment, a # at the beginning of a line or after whitespace that is not quoted
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// Diff - keys that are added or changed, removed keys are returned separately as they cannot be unset
+func Diff(prev, curr map[string]string) (changed map[string]string, removed []string) {
+	changed = make(map[string]string)
+	for k, v := range curr {
+		if old, ok := prev[k]; !ok || old != v {
+			changed[k] = v
+		}
+	}
+	for k := range prev {
+		if _, ok := curr[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	sort.Strings(removed)
+	return changed, removed
+}
@@ -0,0 +1,198 @@
+package loader
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github.com/behavioral-ai/collective/repository"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/module"
+	"github.com/behavioral-ai/intermediary/probation"
+	"github.com/behavioral-ai/intermediary/representation"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultInterval = time.Second * 5
+)
+
+var (
+	// Keys sent with every change of an agent configuration
+	reattached = []string{representation.VersionKey, probation.GuardKey, probation.GuardErrorRateKey, probation.GuardLatencyKey, probation.GuardMinimumKey}
+)
+
+// Loader - file based agent configuration, sent as ConfigEvent messages on load, and as diffs when the file changes
+type Loader struct {
+	name     string
+	interval time.Duration
+	agent    func(name string) messaging.Agent
+	logf     func(format string, v ...any)
+
+	mu      sync.Mutex
+	hash    [sha256.Size]byte
+	current Config // Configuration of each agent, as last sent to the agent
+	pending bool   // An agent was not found, and the file is applied again on the next reload
+	stop    chan struct{}
+}
+
+// New - create a loader for a file, polled at an interval
+func New(name string, interval time.Duration) *Loader {
+	l := new(Loader)
+	l.name = name
+	l.interval = interval
+	if l.interval <= 0 {
+		l.interval = defaultInterval
+	}
+	l.agent = repository.Agent
+	l.logf = log.Printf
+	l.current = make(Config)
+	return l
+}
+
+// Load - read the file, and send the configuration of each agent
+func (l *Loader) Load() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := l.reload(true)
+	return err
+}
+
+// Run - poll the file for changes until stopped
+func (l *Loader) Run() {
+	l.mu.Lock()
+	if l.stop != nil {
+		l.mu.Unlock()
+		return
+	}
+	l.stop = make(chan struct{})
+	stop := l.stop
+	l.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(l.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if _, err := l.Reload(); err != nil {
+					l.logf("loader: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop - stop polling
+func (l *Loader) Stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stop != nil {
+		close(l.stop)
+		l.stop = nil
+	}
+}
+
+// Reload - apply changes if the file content hash has changed, or an agent was not found on the last reload,
+// returns true if changed
+func (l *Loader) Reload() (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.reload(false)
+}
+
+func (l *Loader) reload(force bool) (bool, error) {
+	// The file is small, so the content hash is compared rather than relying on the modification time granularity
+	buf, err := os.ReadFile(l.name)
+	if err != nil {
+		return false, err
+	}
+	hash := sha256.Sum256(buf)
+	changed := force || hash != l.hash
+	if !changed && !l.pending {
+		return false, nil
+	}
+	cfg, err := Parse(l.name, buf)
+	if err == nil {
+		cfg, err = resolve(cfg)
+	}
+	if err != nil {
+		return false, errors.New(fmt.Sprintf("invalid configuration file [%v]: %v", l.name, err))
+	}
+	l.hash = hash
+	l.pending = false
+	names := make([]string, 0, len(cfg))
+	for name := range cfg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		diff, removed := Diff(l.current[name], cfg[name])
+		if changed {
+			for _, k := range removed {
+				l.logf("loader: removed key is not unset [%v] [%v]", name, k)
+			}
+		}
+		// A partial map needs the version to be migrated, and the guard keys for the change to be on probation
+		if len(diff) > 0 {
+			for _, k := range reattached {
+				if v, ok := cfg[name][k]; ok {
+					diff[k] = v
+				}
+			}
+		}
+		// Only a configuration received by the agent is current, otherwise the change is applied again
+		if l.apply(name, diff) || len(diff) == 0 {
+			l.current[name] = cfg[name]
+		}
+	}
+	for name := range l.current {
+		if _, ok := cfg[name]; !ok {
+			delete(l.current, name)
+		}
+	}
+	return changed, nil
+}
+
+// resolve - key the configuration by namespace name, agents may be configured by alias, "cache#orders"
+func resolve(cfg Config) (Config, error) {
+	resolved := make(Config)
+	for name, m := range cfg {
+		ns := module.NamespaceName(name)
+		if _, ok := resolved[ns]; ok {
+			return nil, errors.New(fmt.Sprintf("agent configured more than once [%v]", ns))
+		}
+		resolved[ns] = m
+	}
+	return resolved, nil
+}
+
+// apply - send the valid keys to the agent, and log invalid keys, returns true if the agent was sent a message
+func (l *Loader) apply(name string, m map[string]string) bool {
+	if len(m) == 0 {
+		return false
+	}
+	valid, errs := Validate(name, m)
+	for _, err := range errs {
+		l.logf("loader: %v", err)
+	}
+	if len(valid) == 0 {
+		return false
+	}
+	agent := l.agent(name)
+	if agent == nil {
+		l.logf("loader: agent not found [%v]", name)
+		l.pending = true
+		return false
+	}
+	// Identify the file in the agent configuration history
+	msg := messaging.NewMapMessage(valid)
+	msg.SetFrom(l.name)
+	agent.Message(msg)
+	return true
+}
@@ -0,0 +1,198 @@
+package loader
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/core/messaging"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+type recorder struct {
+	name string
+}
+
+func (r *recorder) Name() string { return r.name }
+func (r *recorder) Message(m *messaging.Message) {
+	cfg, _ := messaging.MapContent(m)
+	var keys []string
+	for k, v := range cfg {
+		keys = append(keys, k+":"+v)
+	}
+	sort.Strings(keys)
+	fmt.Printf("test: Message() -> [%v] %v [source:%v]\n", r.name, keys, filepath.Base(m.From()))
+}
+
+func ExampleParse() {
+	cfg, err := Parse("agents.yaml", []byte(`
+# cache for the orders upstream
+test:resiliency:agent/cache/request/http#orders:
+  host: "localhost:8082" # primary
+  timeout: 750ms   # p99
+  path: "/orders#summary"
+
+test:resiliency:agent/routing/request/http:
+  app-host: localhost:8081
+`))
+	fmt.Printf("test: Parse(yaml) -> %v [err:%v]\n", cfg, err)
+
+	cfg, err = Parse("agents.json", []byte(`{"test:resiliency:agent/routing/request/http": {"app-host": "localhost:8081"}}`))
+	fmt.Printf("test: Parse(json) -> %v [err:%v]\n", cfg, err)
+
+	_, err = Parse("agents.yml", []byte("  host: localhost:8082\n"))
+	fmt.Printf("test: Parse(yml) -> [err:%v]\n", err)
+
+	_, err = Parse("agents.yml", []byte("cache:\n  hosts: [a, b]\n"))
+	fmt.Printf("test: Parse(yml) -> [err:%v]\n", err)
+
+	_, err = Parse("agents.yml", []byte("cache:\n  limits:\n    max: 10\n"))
+	fmt.Printf("test: Parse(yml) -> [err:%v]\n", err)
+
+	//Output:
+	//test: Parse(yaml) -> map[test:resiliency:agent/cache/request/http#orders:map[host:localhost:8082 path:/orders#summary timeout:750ms] test:resiliency:agent/routing/request/http:map[app-host:localhost:8081]] [err:<nil>]
+	//test: Parse(json) -> map[test:resiliency:agent/routing/request/http:map[app-host:localhost:8081]] [err:<nil>]
+	//test: Parse(yml) -> [err:key without an agent name, line 1]
+	//test: Parse(yml) -> [err:unsupported YAML, values must be scalars, line 2]
+	//test: Parse(yml) -> [err:unsupported YAML, nested mappings are not supported, line 3]
+
+}
+
+func ExampleLoader() {
+	name := filepath.Join(os.TempDir(), "loader-example.json")
+	os.WriteFile(name, []byte(`{
+  "test:resiliency:agent/cache/request/http": {"host": "localhost:8082", "timeout": "750ms", "mon": "8-16", "hosts": "x"},
+  "test:resiliency:agent/routing/request/http": {"app-host": "localhost:8081", "log": "yes"}
+}`), 0644)
+	defer os.Remove(name)
+
+	l := New(name, time.Second)
+	l.agent = func(name string) messaging.Agent { return &recorder{name: name} }
+	l.logf = func(format string, v ...any) { fmt.Printf("test: log() -> "+format+"\n", v...) }
+
+	err := l.Load()
+	fmt.Printf("test: Load() -> [err:%v]\n", err)
+
+	ok, err := l.Reload()
+	fmt.Printf("test: Reload() -> [changed:%v] [err:%v]\n", ok, err)
+
+	// Agents are configured by alias
+	os.WriteFile(name, []byte(`{
+  "cache": {"host": "localhost:8082", "timeout": "1500ms"},
+  "routing": {"app-host": "localhost:8081", "log": "yes"}
+}`), 0644)
+	ok, err = l.Reload()
+	fmt.Printf("test: Reload() -> [changed:%v] [err:%v]\n", ok, err)
+
+	// The guard is sent with each change, not only with the change that added it
+	os.WriteFile(name, []byte(`{
+  "cache": {"host": "localhost:8082", "timeout": "1500ms", "guard": "5m"},
+  "routing": {"app-host": "localhost:8081", "log": "yes"}
+}`), 0644)
+	l.Reload()
+	os.WriteFile(name, []byte(`{
+  "cache": {"host": "localhost:8082", "timeout": "2000ms", "guard": "5m"},
+  "routing": {"app-host": "localhost:8081", "log": "yes"}
+}`), 0644)
+	ok, err = l.Reload()
+	fmt.Printf("test: Reload() -> [changed:%v] [err:%v]\n", ok, err)
+
+	os.WriteFile(name, []byte(`{
+  "cache": {"host": "localhost:8082"},
+  "test:resiliency:agent/cache/request/http": {"host": "localhost:8083"}
+}`), 0644)
+	ok, err = l.Reload()
+	fmt.Printf("test: Reload() -> [changed:%v] [err:%v]\n", ok, err != nil)
+
+	//Output:
+	//test: log() -> loader: invalid key [test:resiliency:agent/cache/request/http] [hosts]
+	//test: Message() -> [test:resiliency:agent/cache/request/http] [host:localhost:8082 schedule:mon:8-16 timeout:750ms version:v2] [source:loader-example.json]
+	//test: log() -> loader: invalid value [test:resiliency:agent/routing/request/http] [log:yes] expected true or false
+	//test: Message() -> [test:resiliency:agent/routing/request/http] [routes:app:localhost:8081 version:v2] [source:loader-example.json]
+	//test: Load() -> [err:<nil>]
+	//test: Reload() -> [changed:false] [err:<nil>]
+	//test: log() -> loader: removed key is not unset [test:resiliency:agent/cache/request/http] [hosts]
+	//test: log() -> loader: removed key is not unset [test:resiliency:agent/cache/request/http] [mon]
+	//test: Message() -> [test:resiliency:agent/cache/request/http] [timeout:1500ms version:v2] [source:loader-example.json]
+	//test: Reload() -> [changed:true] [err:<nil>]
+	//test: Message() -> [test:resiliency:agent/cache/request/http] [guard:5m version:v2] [source:loader-example.json]
+	//test: Message() -> [test:resiliency:agent/cache/request/http] [guard:5m timeout:2000ms version:v2] [source:loader-example.json]
+	//test: Reload() -> [changed:true] [err:<nil>]
+	//test: Reload() -> [changed:false] [err:true]
+
+}
+
+func ExampleValidate() {
+	valid, errs := Validate("test:resiliency:agent/routing/request/http", map[string]string{
+		"version": "v2",
+		"routes":  "app:localhost:8081|mirror:localhost:8083",
+		"log":     "false",
+	})
+	fmt.Printf("test: Validate(v2) -> %v %v\n", valid, errs)
+
+	_, errs = Validate("test:resiliency:agent/cache/request/http", map[string]string{"version": "v2", "schedule": "mon8-16"})
+	fmt.Printf("test: Validate(v2) -> %v\n", errs)
+
+	valid, errs = Validate("test:resiliency:agent/cache/request/http", map[string]string{"timeout": "1500ms", "guard": "5m", "guard-min-requests": "x"})
+	fmt.Printf("test: Validate(guard) -> %v %v\n", valid, errs)
+
+	valid, errs = Validate("cache#orders", map[string]string{"timeout": "1500ms"})
+	fmt.Printf("test: Validate(alias) -> %v %v\n", valid, errs)
+
+	//Output:
+	//test: Validate(v2) -> map[log:false routes:app:localhost:8081|mirror:localhost:8083 version:v2] []
+	//test: Validate(v2) -> [invalid configuration [test:resiliency:agent/cache/request/http] invalid schedule [mon8-16]]
+	//test: Validate(guard) -> map[guard:5m timeout:1500ms version:v2] [invalid value [test:resiliency:agent/cache/request/http] [guard-min-requests:x] strconv.Atoi: parsing "x": invalid syntax]
+	//test: Validate(alias) -> map[timeout:1500ms version:v2] []
+
+}
+
+func ExampleLoader_Reload() {
+	name := filepath.Join(os.TempDir(), "loader-reload-example.json")
+	os.WriteFile(name, []byte(`{
+  "cache": {"host": "localhost:8082"},
+  "routing": {"app-host": "localhost:8081"}
+}`), 0644)
+	defer os.Remove(name)
+	info, _ := os.Stat(name)
+
+	registered := false
+	l := New(name, time.Second)
+	l.agent = func(name string) messaging.Agent {
+		if !registered && name == "test:resiliency:agent/routing/request/http" {
+			return nil
+		}
+		return &recorder{name: name}
+	}
+	l.logf = func(format string, v ...any) { fmt.Printf("test: log() -> "+format+"\n", v...) }
+
+	err := l.Load()
+	fmt.Printf("test: Load() -> [err:%v]\n", err)
+
+	// An agent that was not found is sent its configuration once registered
+	registered = true
+	ok, err := l.Reload()
+	fmt.Printf("test: Reload() -> [changed:%v] [err:%v]\n", ok, err)
+	ok, err = l.Reload()
+	fmt.Printf("test: Reload() -> [changed:%v] [err:%v]\n", ok, err)
+
+	// A change within the modification time granularity is applied
+	os.WriteFile(name, []byte(`{
+  "cache": {"host": "localhost:8083"},
+  "routing": {"app-host": "localhost:8081"}
+}`), 0644)
+	os.Chtimes(name, info.ModTime(), info.ModTime())
+	ok, err = l.Reload()
+	fmt.Printf("test: Reload() -> [changed:%v] [err:%v]\n", ok, err)
+
+	//Output:
+	//test: Message() -> [test:resiliency:agent/cache/request/http] [host:localhost:8082 version:v2] [source:loader-reload-example.json]
+	//test: log() -> loader: agent not found [test:resiliency:agent/routing/request/http]
+	//test: Load() -> [err:<nil>]
+	//test: Message() -> [test:resiliency:agent/routing/request/http] [routes:app:localhost:8081 version:v2] [source:loader-reload-example.json]
+	//test: Reload() -> [changed:false] [err:<nil>]
+	//test: Reload() -> [changed:false] [err:<nil>]
+	//test: Message() -> [test:resiliency:agent/cache/request/http] [host:localhost:8083 version:v2] [source:loader-reload-example.json]
+	//test: Reload() -> [changed:true] [err:<nil>]
+
+}
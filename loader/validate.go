@@ -0,0 +1,80 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"github.com/behavioral-ai/core/fmtx"
+	"github.com/behavioral-ai/intermediary/module"
+	"github.com/behavioral-ai/intermediary/representation"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate - validate agent configuration against the agent descriptor, returning the valid keys, and an error
+// for each invalid key. The name may be an alias. A configuration of any representation version is migrated, and
+// the keys of the latest version are returned.
+func Validate(name string, m map[string]string) (map[string]string, []error) {
+	d, ok := module.Resolve(module.NamespaceName(name))
+	if !ok {
+		return nil, []error{errors.New(fmt.Sprintf("agent not found [%v]", name))}
+	}
+	if d.Migrate != nil {
+		var err error
+		m, err = d.Migrate(m)
+		if err != nil {
+			return nil, []error{errors.New(fmt.Sprintf("invalid configuration [%v] %v", name, err))}
+		}
+	}
+	var errs []error
+	valid := make(map[string]string)
+	for k, v := range m {
+		if k == representation.VersionKey {
+			valid[k] = v
+			continue
+		}
+		key, ok1 := d.Key(k)
+		if !ok1 {
+			errs = append(errs, errors.New(fmt.Sprintf("invalid key [%v] [%v]", name, k)))
+			continue
+		}
+		if err := validateValue(key.Type, v); err != nil {
+			errs = append(errs, errors.New(fmt.Sprintf("invalid value [%v] [%v:%v] %v", name, k, v, err)))
+			continue
+		}
+		valid[k] = v
+	}
+	return valid, errs
+}
+
+func validateValue(t, v string) error {
+	var err error
+	switch t {
+	case module.TypeBool:
+		if v != "true" && v != "false" {
+			err = errors.New("expected true or false")
+		}
+	case module.TypeInt:
+		_, err = strconv.Atoi(v)
+	case module.TypeFloat:
+		_, err = strconv.ParseFloat(v, 64)
+	case module.TypeDuration:
+		_, err = fmtx.ParseDuration(v)
+	case module.TypeRegexp:
+		_, err = regexp.Compile(v)
+	case module.TypeTime:
+		if _, err1 := time.Parse(time.RFC3339, v); err1 != nil {
+			_, err = fmtx.ParseDuration(v)
+		}
+	case module.TypeHours:
+		from, to, ok := strings.Cut(v, "-")
+		if !ok {
+			return errors.New("expected hour range")
+		}
+		if _, err = strconv.Atoi(strings.TrimSpace(from)); err == nil {
+			_, err = strconv.Atoi(strings.TrimSpace(to))
+		}
+	}
+	return err
+}
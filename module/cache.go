@@ -0,0 +1,38 @@
+package module
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/cache"
+	"github.com/behavioral-ai/intermediary/cache/representation1"
+	"github.com/behavioral-ai/intermediary/cache/representation2"
+	"github.com/behavioral-ai/intermediary/config"
+)
+
+func cacheDescriptor() Descriptor {
+	c := representation2.Initialize(nil)
+	m := c.Map()
+	return Descriptor{
+		Name:        cache.NamespaceName,
+		Constructor: cache.Constructor,
+		NewInstance: cache.NewInstance,
+		Migrate:     representation2.Schema.Migrate,
+		Keys: []Key{
+			{representation1.HostKey, TypeString, c.Host},
+			{representation1.CacheControlKey, TypeString, c.Policy.Get(representation1.CacheControlKey)},
+			{representation1.TimeoutKey, TypeDuration, config.FormatDuration(c.Timeout)},
+			{representation1.IntervalKey, TypeDuration, config.FormatDuration(c.Interval)},
+			{representation2.ScheduleKey, TypeList, ""},
+			{representation1.ModeKey, TypeString, c.Mode},
+			{representation1.ModeExpiryKey, TypeTime, ""},
+			{representation1.VariantKey, TypeString, c.Variant},
+			{representation2.TTLPolicyKey, TypeList, m[representation2.TTLPolicyKey]},
+			{representation1.TimeoutModeKey, TypeString, c.Adaptive.Mode},
+			{representation1.TimeoutPercentileKey, TypeFloat, fmt.Sprintf("%v", c.Adaptive.Percentile)},
+			{representation1.TimeoutFactorKey, TypeFloat, fmt.Sprintf("%v", c.Adaptive.Factor)},
+			{representation1.TimeoutMinKey, TypeDuration, config.FormatDuration(c.Adaptive.Min)},
+			{representation1.TimeoutMaxKey, TypeDuration, config.FormatDuration(c.Adaptive.Max)},
+		},
+		Events: []string{messaging.ConfigEvent, config.QueryEvent, config.HistoryEvent, config.RollbackEvent, messaging.StartupEvent, messaging.ShutdownEvent, messaging.PauseEvent, messaging.ResumeEvent},
+	}
+}
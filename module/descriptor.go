@@ -0,0 +1,59 @@
+package module
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/probation"
+	"strconv"
+)
+
+const (
+	TypeString   = "string"
+	TypeBool     = "bool"
+	TypeInt      = "int"
+	TypeFloat    = "float"
+	TypeDuration = "duration" // Integer with a ms, s, or m suffix
+	TypeTime     = "time"     // RFC3339 timestamp, or a duration from now
+	TypeHours    = "hours"    // Hour range, "8-16"
+	TypeList     = "list"     // "item|item"
+	TypeRegexp   = "regexp"
+)
+
+// Key - supported configuration key
+type Key struct {
+	Name    string
+	Type    string
+	Default string
+}
+
+// Descriptor - agent metadata
+type Descriptor struct {
+	Name        string
+	Constructor func() messaging.Agent
+	NewInstance func(instance string) messaging.Agent // Named instance, namespace name with an instance suffix
+	Keys        []Key
+	Migrate     func(m map[string]string) (map[string]string, error) // Migrate a map of any version to the latest version of Keys
+	Events      []string
+	Terminal    bool // Terminal Exchange, otherwise a Link stage
+}
+
+// Key - lookup a configuration key, the keys of a guarded change are supported by all agents
+func (d Descriptor) Key(name string) (Key, bool) {
+	for _, k := range append(d.Keys, guardKeys...) {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// guardKeys - probation keys of a guarded change, see probation.Guard
+var guardKeys = func() []Key {
+	_, p, _ := probation.Guard(nil)
+	return []Key{
+		{probation.GuardKey, TypeDuration, ""},
+		{probation.GuardErrorRateKey, TypeFloat, fmt.Sprintf("%v", p.ErrorRate)},
+		{probation.GuardLatencyKey, TypeFloat, fmt.Sprintf("%v", p.Latency)},
+		{probation.GuardMinimumKey, TypeInt, strconv.Itoa(p.Minimum)},
+	}
+}()
@@ -2,21 +2,53 @@ package module
 
 import (
 	"github.com/behavioral-ai/intermediary/cache"
+	"github.com/behavioral-ai/intermediary/namespace"
 	"github.com/behavioral-ai/intermediary/routing"
+	"github.com/behavioral-ai/intermediary/shedding"
 )
 
 var (
-	CacheNamespaceName   = cache.NamespaceName
-	RoutingNamespaceName = routing.NamespaceName
+	CacheNamespaceName    = cache.NamespaceName
+	RoutingNamespaceName  = routing.NamespaceName
+	SheddingNamespaceName = shedding.NamespaceName
 )
 
-func Resolve(name string) (bool, any) {
-	switch name {
-	case cache.NamespaceName:
-		return true, nil
-	case routing.NamespaceName:
-		return true, nil
-	default:
-		return false, nil
+// aliases - short names for agent namespace names
+var aliases = map[string]string{
+	"cache":    CacheNamespaceName,
+	"routing":  RoutingNamespaceName,
+	"shedding": SheddingNamespaceName,
+}
+
+// NamespaceName - resolve an alias to a namespace name, preserving the instance of a named instance, "cache#orders".
+// A name that is not an alias is returned as is.
+func NamespaceName(name string) string {
+	alias, instance := namespace.Split(name)
+	if ns, ok := aliases[alias]; ok {
+		return namespace.Instance(ns, instance)
 	}
+	return name
+}
+
+// descriptors - in pipeline order, link stages followed by the terminal exchange
+var descriptors = []Descriptor{
+	sheddingDescriptor(),
+	cacheDescriptor(),
+	routingDescriptor(),
+}
+
+// Resolve - agent descriptor for a namespace name, the instance of a named instance is ignored
+func Resolve(name string) (Descriptor, bool) {
+	name, _ = namespace.Split(name)
+	for _, d := range descriptors {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Descriptor{}, false
+}
+
+// Descriptors - all agent descriptors
+func Descriptors() []Descriptor {
+	return append([]Descriptor(nil), descriptors...)
 }
@@ -0,0 +1,41 @@
+package module
+
+import (
+	"fmt"
+)
+
+func ExampleResolve() {
+	d, ok := Resolve(CacheNamespaceName)
+	k, _ := d.Key("timeout")
+	fmt.Printf("test: Resolve() -> [name:%v] [terminal:%v] [events:%v] [ok:%v]\n", d.Name, d.Terminal, len(d.Events), ok)
+	fmt.Printf("test: Key() -> %v\n", k)
+	fmt.Printf("test: Constructor() -> %v\n", d.Constructor().Name())
+
+	d, ok = Resolve(RoutingNamespaceName)
+	k, _ = d.Key("failover-status-codes")
+	fmt.Printf("test: Resolve() -> [name:%v] [terminal:%v] [events:%v] [ok:%v]\n", d.Name, d.Terminal, len(d.Events), ok)
+	fmt.Printf("test: Key() -> %v\n", k)
+
+	k, ok = d.Key("guard")
+	fmt.Printf("test: Key() -> %v [ok:%v]\n", k, ok)
+
+	_, ok = Resolve("test:resiliency:agent/unknown")
+	fmt.Printf("test: Resolve() -> [ok:%v]\n", ok)
+
+	for _, d = range Descriptors() {
+		fmt.Printf("test: Descriptors() -> [name:%v] [terminal:%v]\n", d.Name, d.Terminal)
+	}
+
+	//Output:
+	//test: Resolve() -> [name:test:resiliency:agent/cache/request/http] [terminal:false] [events:8] [ok:true]
+	//test: Key() -> {timeout duration 2s}
+	//test: Constructor() -> test:resiliency:agent/cache/request/http
+	//test: Resolve() -> [name:test:resiliency:agent/routing/request/http] [terminal:true] [events:8] [ok:true]
+	//test: Key() -> {failover-status-codes list 502|503|504}
+	//test: Key() -> {guard duration } [ok:true]
+	//test: Resolve() -> [ok:false]
+	//test: Descriptors() -> [name:test:resiliency:agent/shedding/request/http] [terminal:false]
+	//test: Descriptors() -> [name:test:resiliency:agent/cache/request/http] [terminal:false]
+	//test: Descriptors() -> [name:test:resiliency:agent/routing/request/http] [terminal:true]
+
+}
@@ -0,0 +1,75 @@
+package module
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/config"
+	"github.com/behavioral-ai/intermediary/routing"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"github.com/behavioral-ai/intermediary/routing/representation2"
+	"strconv"
+)
+
+func routingDescriptor() Descriptor {
+	r := representation2.Initialize(nil)
+	var codes []string
+	for _, code := range r.Failover.StatusCodes {
+		codes = append(codes, strconv.Itoa(code))
+	}
+	return Descriptor{
+		Name:        routing.NamespaceName,
+		Constructor: routing.Constructor,
+		NewInstance: routing.NewInstance,
+		Migrate:     representation2.Schema.Migrate,
+		Keys: []Key{
+			{representation2.RoutesKey, TypeList, ""},
+			{representation1.LogKey, TypeBool, strconv.FormatBool(r.Log)},
+			{representation1.LogRouteKey, TypeString, r.LogRouteName},
+			{representation1.TimeoutKey, TypeDuration, config.FormatDuration(r.Timeout)},
+			{representation1.IntervalKey, TypeDuration, config.FormatDuration(r.Interval)},
+			{representation1.TimeoutModeKey, TypeString, r.Adaptive.Mode},
+			{representation1.TimeoutPercentileKey, TypeFloat, fmt.Sprintf("%v", r.Adaptive.Percentile)},
+			{representation1.TimeoutFactorKey, TypeFloat, fmt.Sprintf("%v", r.Adaptive.Factor)},
+			{representation1.TimeoutMinKey, TypeDuration, config.FormatDuration(r.Adaptive.Min)},
+			{representation1.TimeoutMaxKey, TypeDuration, config.FormatDuration(r.Adaptive.Max)},
+			{representation1.ForwardedKey, TypeBool, strconv.FormatBool(r.Forwarded)},
+			{representation1.RequestHeaderAddKey, TypeList, ""},
+			{representation1.RequestHeaderSetKey, TypeList, ""},
+			{representation1.RequestHeaderRmKey, TypeList, ""},
+			{representation1.ResponseHeaderAddKey, TypeList, ""},
+			{representation1.ResponseHeaderSetKey, TypeList, ""},
+			{representation1.ResponseHeaderRmKey, TypeList, ""},
+			{representation1.PathStripPrefixKey, TypeString, ""},
+			{representation1.PathAddPrefixKey, TypeString, ""},
+			{representation1.PathPatternKey, TypeRegexp, ""},
+			{representation1.PathReplaceKey, TypeString, ""},
+			{representation1.QueryAddKey, TypeList, ""},
+			{representation1.QueryRemoveKey, TypeList, ""},
+			{representation1.MirrorPercentageKey, TypeInt, strconv.Itoa(r.MirrorPercentage)},
+			{representation1.MirrorMethodsKey, TypeList, config.FormatList(r.MirrorMethods)},
+			{representation1.CanaryPercentageKey, TypeInt, strconv.Itoa(r.Canary.Percentage)},
+			{representation1.CanaryHeaderKey, TypeString, ""},
+			{representation1.CanaryCookieKey, TypeString, ""},
+			{representation1.CanaryClientKey, TypeString, r.Canary.ClientKey},
+			{representation1.FailoverHostsKey, TypeList, config.FormatList(r.Failover.Hosts)},
+			{representation1.FailoverStatusKey, TypeList, config.FormatList(codes)},
+			{representation1.HealthPathKey, TypeString, r.Failover.HealthPath},
+			{representation1.HealthIntervalKey, TypeDuration, config.FormatDuration(r.Failover.HealthInterval)},
+			{representation1.HedgePercentileKey, TypeInt, strconv.Itoa(r.Hedge.Percentile)},
+			{representation1.HedgeBudgetKey, TypeInt, strconv.Itoa(r.Hedge.Budget)},
+			{representation1.HedgeMinDelayKey, TypeDuration, config.FormatDuration(r.Hedge.MinDelay)},
+			{representation1.LimitKey, TypeString, r.Limit.Mode},
+			{representation1.LimitInitialKey, TypeInt, strconv.Itoa(r.Limit.Initial)},
+			{representation1.LimitMinKey, TypeInt, strconv.Itoa(r.Limit.Min)},
+			{representation1.LimitMaxKey, TypeInt, strconv.Itoa(r.Limit.Max)},
+			{representation1.LimitLatencyKey, TypeDuration, config.FormatDuration(r.Limit.Latency)},
+			{representation1.LimitQueueKey, TypeInt, strconv.Itoa(r.Limit.Queue)},
+			{representation1.LimitQueueWaitKey, TypeDuration, config.FormatDuration(r.Limit.QueueWait)},
+			{representation1.CompressKey, TypeBool, strconv.FormatBool(r.Compress.Enabled)},
+			{representation1.CompressTypesKey, TypeList, config.FormatList(r.Compress.Types)},
+			{representation1.CompressMinSizeKey, TypeInt, strconv.Itoa(r.Compress.MinSize)},
+		},
+		Events:   []string{messaging.ConfigEvent, config.QueryEvent, config.HistoryEvent, config.RollbackEvent, messaging.StartupEvent, messaging.ShutdownEvent, messaging.PauseEvent, messaging.ResumeEvent},
+		Terminal: true,
+	}
+}
@@ -0,0 +1,29 @@
+package module
+
+import (
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/config"
+	"github.com/behavioral-ai/intermediary/shedding"
+	"github.com/behavioral-ai/intermediary/shedding/representation1"
+	"github.com/behavioral-ai/intermediary/shedding/representation2"
+)
+
+func sheddingDescriptor() Descriptor {
+	s := representation2.Initialize(nil)
+	m := s.Map()
+	return Descriptor{
+		Name:        shedding.NamespaceName,
+		Constructor: shedding.Constructor,
+		NewInstance: shedding.NewInstance,
+		Migrate:     representation2.Schema.Migrate,
+		Keys: []Key{
+			{representation1.PriorityHeaderKey, TypeString, s.PriorityHeader},
+			{representation1.HighPathKey, TypeRegexp, ""},
+			{representation1.LowPathKey, TypeRegexp, ""},
+			{representation1.LowMethodsKey, TypeList, config.FormatList(s.LowMethods)},
+			{representation2.ThresholdsKey, TypeList, m[representation2.ThresholdsKey]},
+			{representation1.IntervalKey, TypeDuration, config.FormatDuration(s.Interval)},
+		},
+		Events: []string{messaging.ConfigEvent, config.QueryEvent, config.HistoryEvent, config.RollbackEvent, messaging.StartupEvent, messaging.ShutdownEvent, messaging.PauseEvent, messaging.ResumeEvent},
+	}
+}
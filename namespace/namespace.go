@@ -0,0 +1,23 @@
+package namespace
+
+import (
+	"strings"
+)
+
+const (
+	InstanceSeparator = "#"
+)
+
+// Instance - namespace name of a named agent instance, an empty instance returns the namespace name
+func Instance(name, instance string) string {
+	if instance == "" {
+		return name
+	}
+	return name + InstanceSeparator + instance
+}
+
+// Split - split a namespace name into the agent namespace name and the instance
+func Split(name string) (string, string) {
+	ns, instance, _ := strings.Cut(name, InstanceSeparator)
+	return ns, instance
+}
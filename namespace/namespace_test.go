@@ -0,0 +1,22 @@
+package namespace
+
+import (
+	"fmt"
+)
+
+func ExampleInstance() {
+	name := "test:resiliency:agent/cache/request/http"
+
+	s := Instance(name, "orders")
+	ns, instance := Split(s)
+	fmt.Printf("test: Instance() -> [%v] [ns:%v] [instance:%v]\n", s, ns, instance)
+
+	s = Instance(name, "")
+	ns, instance = Split(s)
+	fmt.Printf("test: Instance() -> [%v] [ns:%v] [instance:%v]\n", s, ns, instance)
+
+	//Output:
+	//test: Instance() -> [test:resiliency:agent/cache/request/http#orders] [ns:test:resiliency:agent/cache/request/http] [instance:orders]
+	//test: Instance() -> [test:resiliency:agent/cache/request/http] [ns:test:resiliency:agent/cache/request/http] [instance:]
+
+}
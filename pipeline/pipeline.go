@@ -0,0 +1,227 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"github.com/behavioral-ai/collective/repository"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/core/rest"
+	"github.com/behavioral-ai/intermediary/module"
+	"github.com/behavioral-ai/intermediary/namespace"
+	"github.com/behavioral-ai/intermediary/request"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Linker - chainable stage
+type Linker interface {
+	Link(next rest.Exchange) rest.Exchange
+}
+
+// Exchanger - terminal stage
+type Exchanger interface {
+	Exchange(r *http.Request) (*http.Response, error)
+}
+
+// chain - a built exchange. The pipeline holds a reference to the current chain, and each request in flight holds a
+// reference until its response body is closed. Once a replaced chain is released by all of its requests, the
+// retired agents are shut down.
+type chain struct {
+	names   []string
+	agents  []messaging.Agent
+	ex      rest.Exchange
+	refs    atomic.Int64
+	retired []messaging.Agent
+	drained func(c *chain)
+}
+
+// acquire - returns false if the chain has been released
+func (c *chain) acquire() bool {
+	for {
+		n := c.refs.Load()
+		if n == 0 {
+			return false
+		}
+		if c.refs.CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+func (c *chain) release() {
+	if c.refs.Add(-1) == 0 && c.drained != nil {
+		c.drained(c)
+	}
+}
+
+// Pipeline - an exchange composed from an ordered list of agents, that can be rebuilt atomically
+type Pipeline struct {
+	mu      sync.Mutex // Serializes rebuilds with startup and shutdown
+	running bool
+	current atomic.Pointer[chain]
+}
+
+// New - create a pipeline from agent namespace names
+func New(names []string) (*Pipeline, error) {
+	c, err := build(names)
+	if err != nil {
+		return nil, err
+	}
+	p := new(Pipeline)
+	c.refs.Store(1)
+	p.current.Store(c)
+	return p, nil
+}
+
+// Build - compose agent namespace names into a single exchange. All stages except the last must be Link stages,
+// and the last stage must be a terminal Exchange.
+func Build(names []string) (rest.Exchange, error) {
+	c, err := build(names)
+	if err != nil {
+		return nil, err
+	}
+	return c.ex, nil
+}
+
+// Exchange - implementation for rest.Exchangeable interface, requests in flight complete on the chain they started with.
+// The request deadline is derived from the inbound budget header.
+func (p *Pipeline) Exchange(r *http.Request) (*http.Response, error) {
+	r, cancel := request.WithBudget(r)
+	c := p.acquire()
+	resp, err := c.ex(r)
+	request.CancelOnClose(resp, func() {
+		cancel()
+		c.release()
+	})
+	return resp, err
+}
+
+// acquire - reference the current chain, a chain that is replaced and released is never referenced again
+func (p *Pipeline) acquire() *chain {
+	for {
+		if c := p.current.Load(); c.acquire() {
+			return c
+		}
+	}
+}
+
+// Rebuild - replace the chain, the current chain is unchanged if the new definition is invalid. When the pipeline
+// is running, agents added to the chain are started, and agents removed from the chain are shut down once the
+// requests in flight on the previous chain have completed.
+func (p *Pipeline) Rebuild(names []string) error {
+	c, err := build(names)
+	if err != nil {
+		return err
+	}
+	c.refs.Store(1)
+	p.mu.Lock()
+	prev := p.current.Swap(c)
+	if p.running {
+		message(added(prev, c), messaging.StartupMessage)
+		prev.retired = added(c, prev)
+	}
+	prev.drained = p.drained
+	p.mu.Unlock()
+	prev.release()
+	return nil
+}
+
+// drained - shut down the retired agents of a released chain, unless a later rebuild has added them back
+func (p *Pipeline) drained(c *chain) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	current := p.current.Load()
+	for _, a := range c.retired {
+		if !slices.Contains(current.agents, a) {
+			a.Message(messaging.ShutdownMessage)
+		}
+	}
+}
+
+// Startup - start the agents of the current chain
+func (p *Pipeline) Startup() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running = true
+	message(p.current.Load().agents, messaging.StartupMessage)
+}
+
+// Shutdown - shut down the agents of the current chain
+func (p *Pipeline) Shutdown() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running = false
+	message(p.current.Load().agents, messaging.ShutdownMessage)
+}
+
+// Names - agent namespace names of the current chain
+func (p *Pipeline) Names() []string {
+	return append([]string(nil), p.current.Load().names...)
+}
+
+// Agents - agents of the current chain
+func (p *Pipeline) Agents() []messaging.Agent {
+	return append([]messaging.Agent(nil), p.current.Load().agents...)
+}
+
+// added - agents of the next chain that are not in the previous chain
+func added(prev, next *chain) []messaging.Agent {
+	var agents []messaging.Agent
+	for i, name := range next.names {
+		if !slices.Contains(prev.names, name) {
+			agents = append(agents, next.agents[i])
+		}
+	}
+	return agents
+}
+
+func message(agents []messaging.Agent, m *messaging.Message) {
+	for _, a := range agents {
+		a.Message(m)
+	}
+}
+
+// build - a new instance of an agent is only registered in the repository if the chain is built
+func build(names []string) (*chain, error) {
+	if len(names) == 0 {
+		return nil, errors.New("pipeline is empty")
+	}
+	c := &chain{names: append([]string(nil), names...)}
+	instances := make(map[string]messaging.Agent)
+	for _, name := range names {
+		d, ok := module.Resolve(name)
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("agent not found [%v]", name))
+		}
+		agent := repository.Agent(name)
+		if _, instance := namespace.Split(name); agent == nil && instance != "" {
+			if agent, ok = instances[name]; !ok {
+				agent = d.NewInstance(instance)
+				instances[name] = agent
+			}
+		}
+		if agent == nil {
+			return nil, errors.New(fmt.Sprintf("agent constructor not found [%v]", name))
+		}
+		c.agents = append(c.agents, agent)
+	}
+	last := c.agents[len(c.agents)-1]
+	terminal, ok := last.(Exchanger)
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("last agent is not a terminal exchange [%v]", last.Name()))
+	}
+	c.ex = terminal.Exchange
+	for i := len(c.agents) - 2; i >= 0; i-- {
+		l, ok1 := c.agents[i].(Linker)
+		if !ok1 {
+			return nil, errors.New(fmt.Sprintf("agent is not a link stage [%v]", c.agents[i].Name()))
+		}
+		c.ex = l.Link(c.ex)
+	}
+	for name, agent := range instances {
+		repository.RegisterConstructor(name, func() messaging.Agent { return agent })
+	}
+	return c, nil
+}
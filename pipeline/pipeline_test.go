@@ -0,0 +1,190 @@
+package pipeline
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/collective/repository"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/core/rest"
+	"github.com/behavioral-ai/intermediary/cache"
+	"github.com/behavioral-ai/intermediary/request"
+	"github.com/behavioral-ai/intermediary/routing"
+	"github.com/behavioral-ai/intermediary/shedding"
+	"io"
+	"net/http"
+	"strings"
+)
+
+func appExchange(r *http.Request) (*http.Response, error) {
+	return httpx.NewResponse(http.StatusOK, nil, nil), nil
+}
+
+func ExampleNew() {
+	routing.ConstructorOverride(map[string]string{"app-host": "localhost:8081", "log": "false"}, appExchange, operationstest.NewService())
+
+	_, err := New(nil)
+	fmt.Printf("test: New(nil) -> [err:%v]\n", err)
+
+	p, err := New([]string{shedding.NamespaceName, routing.NamespaceName})
+	fmt.Printf("test: New() -> [names:%v] [err:%v]\n", len(p.Names()), err)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/search?q=golang", nil)
+	resp, err := p.Exchange(req)
+	fmt.Printf("test: Exchange() -> [status:%v] [err:%v]\n", resp.StatusCode, err)
+
+	// Request deadline is derived from the inbound budget
+	req2, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/search?q=golang", nil)
+	req2.Header.Add(request.XRequestBudget, "0")
+	resp, err = p.Exchange(req2)
+	fmt.Printf("test: Exchange() -> [budget:0] [status:%v] [err:%v]\n", resp.StatusCode, err)
+
+	err = p.Rebuild([]string{routing.NamespaceName, cache.NamespaceName})
+	fmt.Printf("test: Rebuild() -> [names:%v] [err:%v]\n", len(p.Names()), err)
+
+	err = p.Rebuild([]string{cache.NamespaceName, "test:resiliency:agent/unknown"})
+	fmt.Printf("test: Rebuild() -> [names:%v] [err:%v]\n", len(p.Names()), err)
+
+	err = p.Rebuild([]string{cache.NamespaceName, shedding.NamespaceName, routing.NamespaceName})
+	fmt.Printf("test: Rebuild() -> [names:%v] [err:%v]\n", len(p.Names()), err)
+
+	resp, err = p.Exchange(req)
+	fmt.Printf("test: Exchange() -> [status:%v] [err:%v]\n", resp.StatusCode, err)
+
+	//Output:
+	//test: New(nil) -> [err:pipeline is empty]
+	//test: New() -> [names:2] [err:<nil>]
+	//test: Exchange() -> [status:200] [err:<nil>]
+	//test: Exchange() -> [budget:0] [status:504] [err:context deadline exceeded]
+	//test: Rebuild() -> [names:2] [err:last agent is not a terminal exchange [test:resiliency:agent/cache/request/http]]
+	//test: Rebuild() -> [names:2] [err:agent not found [test:resiliency:agent/unknown]]
+	//test: Rebuild() -> [names:3] [err:<nil>]
+	//test: Exchange() -> [status:200] [err:<nil>]
+
+}
+
+func ExampleNew_instance() {
+	cache.InstanceOverride("orders", map[string]string{"host": "localhost:8082"}, nil, operationstest.NewService())
+	cache.InstanceOverride("search", map[string]string{"host": "localhost:8083"}, nil, operationstest.NewService())
+	orders := cache.NamespaceName + "#orders"
+	search := cache.NamespaceName + "#search"
+	media := shedding.NamespaceName + "#media"
+
+	p, err := New([]string{orders, search, media, routing.NamespaceName})
+	fmt.Printf("test: New() -> [err:%v]\n", err)
+	for _, a := range p.Agents() {
+		fmt.Printf("test: Agents() -> %v\n", a.Name())
+	}
+
+	//Output:
+	//test: New() -> [err:<nil>]
+	//test: Agents() -> test:resiliency:agent/cache/request/http#orders
+	//test: Agents() -> test:resiliency:agent/cache/request/http#search
+	//test: Agents() -> test:resiliency:agent/shedding/request/http#media
+	//test: Agents() -> test:resiliency:agent/routing/request/http
+
+}
+
+// probeAgent - link stage recording lifecycle events
+type probeAgent struct {
+	name   string
+	events []string
+}
+
+func (a *probeAgent) String() string { return a.name }
+func (a *probeAgent) Name() string   { return a.name }
+func (a *probeAgent) Message(m *messaging.Message) {
+	switch m.Name {
+	case messaging.StartupEvent:
+		a.events = append(a.events, "startup")
+	case messaging.ShutdownEvent:
+		a.events = append(a.events, "shutdown")
+	}
+}
+func (a *probeAgent) Link(next rest.Exchange) rest.Exchange { return next }
+
+func ExamplePipeline_Rebuild() {
+	routing.ConstructorOverride(map[string]string{"app-host": "localhost:8081", "log": "false"}, appExchange, operationstest.NewService())
+	probe := func(instance string) (string, *probeAgent) {
+		name := shedding.NamespaceName + "#" + instance
+		a := &probeAgent{name: name}
+		repository.RegisterConstructor(name, func() messaging.Agent { return a })
+		return name, a
+	}
+	first, a1 := probe("first")
+	second, a2 := probe("second")
+	third, a3 := probe("third")
+
+	p, _ := New([]string{first, second, routing.NamespaceName})
+	p.Rebuild([]string{second, first, routing.NamespaceName})
+	fmt.Printf("test: Rebuild() -> [not running] [first:%v] [second:%v]\n", a1.events, a2.events)
+
+	p.Startup()
+	err := p.Rebuild([]string{second, third, routing.NamespaceName})
+	fmt.Printf("test: Rebuild() -> [first:%v] [second:%v] [third:%v] [err:%v]\n", a1.events, a2.events, a3.events, err)
+
+	p.Shutdown()
+	fmt.Printf("test: Shutdown() -> [first:%v] [second:%v] [third:%v]\n", a1.events, a2.events, a3.events)
+
+	//Output:
+	//test: Rebuild() -> [not running] [first:[]] [second:[]]
+	//test: Rebuild() -> [first:[startup shutdown]] [second:[startup]] [third:[startup]] [err:<nil>]
+	//test: Shutdown() -> [first:[startup shutdown]] [second:[startup shutdown]] [third:[startup shutdown]]
+
+}
+
+// streamAgent - link stage with a response body that is read after the exchange returns
+type streamAgent struct {
+	probeAgent
+}
+
+func (a *streamAgent) Link(next rest.Exchange) rest.Exchange {
+	return func(r *http.Request) (*http.Response, error) {
+		resp, err := next(r)
+		if resp != nil {
+			resp.Body = io.NopCloser(strings.NewReader("streaming"))
+		}
+		return resp, err
+	}
+}
+
+func ExamplePipeline_Rebuild_inFlight() {
+	routing.ConstructorOverride(map[string]string{"app-host": "localhost:8081", "log": "false"}, appExchange, operationstest.NewService())
+	name := shedding.NamespaceName + "#stream"
+	a := &streamAgent{probeAgent{name: name}}
+	repository.RegisterConstructor(name, func() messaging.Agent { return a })
+
+	p, _ := New([]string{name, routing.NamespaceName})
+	p.Startup()
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/search?q=golang", nil)
+	resp, _ := p.Exchange(req)
+
+	// The removed agent is shut down once the request in flight on the previous chain completes
+	p.Rebuild([]string{routing.NamespaceName})
+	fmt.Printf("test: Rebuild() -> [stream:%v]\n", a.events)
+	resp.Body.Close()
+	fmt.Printf("test: Close() -> [stream:%v]\n", a.events)
+
+	//Output:
+	//test: Rebuild() -> [stream:[startup]]
+	//test: Close() -> [stream:[startup shutdown]]
+
+}
+
+func ExamplePipeline_Rebuild_invalid() {
+	routing.ConstructorOverride(map[string]string{"app-host": "localhost:8081", "log": "false"}, appExchange, operationstest.NewService())
+	orphan := shedding.NamespaceName + "#orphan"
+
+	// A new instance is not registered if the chain is not built
+	p, _ := New([]string{routing.NamespaceName})
+	err := p.Rebuild([]string{orphan, cache.NamespaceName})
+	fmt.Printf("test: Rebuild() -> [err:%v] [registered:%v]\n", err, repository.Agent(orphan) != nil)
+
+	err = p.Rebuild([]string{orphan, routing.NamespaceName})
+	fmt.Printf("test: Rebuild() -> [err:%v] [registered:%v] [same:%v]\n", err, repository.Agent(orphan) != nil, repository.Agent(orphan) == p.Agents()[0])
+
+	//Output:
+	//test: Rebuild() -> [err:last agent is not a terminal exchange [test:resiliency:agent/cache/request/http]] [registered:false]
+	//test: Rebuild() -> [err:<nil>] [registered:true] [same:true]
+
+}
@@ -0,0 +1,309 @@
+package probation
+
+import (
+	"errors"
+	"fmt"
+	"github.com/behavioral-ai/core/fmtx"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/latency"
+	"maps"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A guarded change is a ConfigEvent with a probation window. After the change is applied, request outcomes are
+// compared with the outcomes before the change, and the previous version is restored if the error rate or the
+// latency degrades beyond the thresholds.
+//
+//	guard              : "5m"
+//	guard-error-rate   : "5"
+//	guard-latency      : "1.5"
+//	guard-min-requests : "20"
+
+const (
+	GuardKey          = "guard"              // Probation window
+	GuardErrorRateKey = "guard-error-rate"   // Maximum error rate increase, in percentage points
+	GuardLatencyKey   = "guard-latency"      // Maximum latency percentile increase, as a factor of the baseline
+	GuardMinimumKey   = "guard-min-requests" // Requests observed before outcomes are compared
+
+	SourceProbation = "probation"
+
+	defaultErrorRate  = 5.0
+	defaultLatency    = 1.5
+	defaultMinimum    = latency.MinimumSamples
+	latencyPercentile = 90
+	evaluateInterval  = time.Millisecond * 250
+	shardCount        = 8
+)
+
+var (
+	keys = []string{GuardKey, GuardErrorRateKey, GuardLatencyKey, GuardMinimumKey}
+)
+
+// Policy - probation window and regression thresholds
+type Policy struct {
+	Window    time.Duration
+	ErrorRate float64
+	Latency   float64
+	Minimum   int
+}
+
+// Guard - copy of a ConfigEvent map without the reserved guard keys, and the policy, false if the change is
+// not guarded
+func Guard(m map[string]string) (map[string]string, Policy, bool) {
+	cfg := maps.Clone(m)
+	for _, k := range keys {
+		delete(cfg, k)
+	}
+	p := Policy{ErrorRate: defaultErrorRate, Latency: defaultLatency, Minimum: defaultMinimum}
+	dur, err := fmtx.ParseDuration(m[GuardKey])
+	if err != nil || dur <= 0 {
+		return cfg, p, false
+	}
+	p.Window = dur
+	if f, err := strconv.ParseFloat(m[GuardErrorRateKey], 64); err == nil && f >= 0 {
+		p.ErrorRate = f
+	}
+	if f, err := strconv.ParseFloat(m[GuardLatencyKey], 64); err == nil && f >= 1 {
+		p.Latency = f
+	}
+	if i, err := strconv.Atoi(m[GuardMinimumKey]); err == nil && i > 0 {
+		p.Minimum = i
+	}
+	return cfg, p, true
+}
+
+// Failure - determine if a request outcome is a failure
+func Failure(resp *http.Response, err error) bool {
+	return err != nil || resp == nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// Result - outcome of an observation for a change on probation
+type Result struct {
+	Passed    bool   // Probation window elapsed without a regression
+	Regressed bool   // Outcomes degraded, the previous version should be restored
+	Version   int    // History version to restore
+	Reason    string // Regression description
+}
+
+// Monitor - request outcomes before and after a guarded change. Outcomes are recorded in the current interval
+// without the monitor lock, and an elapsed interval is added to the baseline, and evaluated for a change on
+// probation, once per interval.
+type Monitor struct {
+	current  atomic.Pointer[interval]
+	interval time.Duration
+
+	mu         sync.Mutex
+	baseline   *window // Rolling outcomes
+	probation  *window // Outcomes since a guarded change, nil if there is no change on probation
+	policy     Policy
+	start      time.Time
+	version    int
+	rate       float64       // Baseline error rate
+	percentile time.Duration // Baseline latency percentile, zero if there were too few observations
+}
+
+// NewMonitor - create a monitor with a rolling baseline of the given size
+func NewMonitor(size int) *Monitor {
+	m := new(Monitor)
+	m.interval = evaluateInterval
+	m.baseline = newWindow(size)
+	m.current.Store(newInterval())
+	return m
+}
+
+// Start - start probation for a change, version is the history version restored on a regression. The baseline
+// is the rolling outcomes before the change, false if the baseline has fewer than the minimum requests, as
+// outcomes could not be compared.
+func (m *Monitor) Start(p Policy, version int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Outcomes before the change are added to the baseline, so that the probation outcomes are after the change
+	m.baseline.add(m.current.Swap(newInterval()))
+	if m.baseline.count() < p.Minimum {
+		m.probation = nil
+		return false
+	}
+	m.policy = p
+	m.version = version
+	m.start = time.Now().UTC()
+	m.rate = m.baseline.errorRate()
+	m.percentile, _ = m.baseline.latency.Percentile(latencyPercentile)
+	m.probation = newWindow(latency.DefaultSize)
+	return true
+}
+
+// Stop - end probation without a result
+func (m *Monitor) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.probation = nil
+}
+
+// Active - determine if a change is on probation
+func (m *Monitor) Active() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.probation != nil
+}
+
+// Observe - add a request outcome, a zero latency is only counted in the error rate. The result is the evaluation
+// of the interval, when the interval has elapsed. A change on probation is passed, or regressed, at most once.
+func (m *Monitor) Observe(d time.Duration, failure bool) Result {
+	i := m.current.Load()
+	i.observe(d, failure)
+	if time.Since(i.start) < m.interval || !m.current.CompareAndSwap(i, newInterval()) {
+		return Result{}
+	}
+	return m.evaluate(i)
+}
+
+// evaluate - add an elapsed interval to the baseline, and to the outcomes of a change on probation
+func (m *Monitor) evaluate(i *interval) Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.baseline.add(i)
+	if m.probation == nil {
+		return Result{}
+	}
+	m.probation.add(i)
+	if reason, ok := m.regression(); ok {
+		m.probation = nil
+		return Result{Regressed: true, Version: m.version, Reason: reason}
+	}
+	if time.Since(m.start) >= m.policy.Window {
+		m.probation = nil
+		return Result{Passed: true, Version: m.version}
+	}
+	return Result{}
+}
+
+func (m *Monitor) regression() (string, bool) {
+	if m.probation.count() < m.policy.Minimum {
+		return "", false
+	}
+	if rate := m.probation.errorRate(); rate-m.rate > m.policy.ErrorRate {
+		return fmt.Sprintf("error rate [%.1f%%] baseline [%.1f%%]", rate, m.rate), true
+	}
+	if m.percentile <= 0 {
+		return "", false
+	}
+	if d, ok := m.probation.latency.Percentile(latencyPercentile); ok && float64(d) > float64(m.percentile)*m.policy.Latency {
+		return fmt.Sprintf("latency p%v [%v] baseline [%v]", latencyPercentile, d, m.percentile), true
+	}
+	return "", false
+}
+
+// NewBaselineStatus - status for a guarded change that is not on probation
+func NewBaselineStatus(p Policy) *messaging.Status {
+	return messaging.NewStatus(messaging.StatusInvalidArgument, errors.New(fmt.Sprintf("configuration not guarded, baseline is less than [%v] requests", p.Minimum)))
+}
+
+// NewRevertStatus - status for a regression
+func NewRevertStatus(r Result) *messaging.Status {
+	return messaging.NewStatus(messaging.StatusInvalidArgument, errors.New(fmt.Sprintf("configuration regression, restoring version [%v] %v", r.Version, r.Reason)))
+}
+
+// interval - request outcomes recorded without the monitor lock, latencies are sharded to reduce contention. An
+// outcome recorded while the interval is being added to a window may not be counted.
+type interval struct {
+	start    time.Time
+	requests atomic.Int64
+	failures atomic.Int64
+	next     atomic.Uint64
+	shards   [shardCount]shard
+}
+
+type shard struct {
+	mu      sync.Mutex
+	samples []time.Duration
+}
+
+func newInterval() *interval {
+	i := new(interval)
+	i.start = time.Now().UTC()
+	return i
+}
+
+func (i *interval) observe(d time.Duration, failure bool) {
+	i.requests.Add(1)
+	if failure {
+		i.failures.Add(1)
+	}
+	if d <= 0 {
+		return
+	}
+	s := &i.shards[i.next.Add(1)%shardCount]
+	s.mu.Lock()
+	s.samples = append(s.samples, d)
+	s.mu.Unlock()
+}
+
+// window - rolling window of request outcomes
+type window struct {
+	latency  *latency.Histogram
+	failures []bool
+	next     int
+	full     bool
+	errors   int
+}
+
+func newWindow(size int) *window {
+	if size <= 0 {
+		size = latency.DefaultSize
+	}
+	w := new(window)
+	w.latency = latency.NewHistogram(size)
+	w.failures = make([]bool, size)
+	return w
+}
+
+// add - add the outcomes of an interval
+func (w *window) add(i *interval) {
+	for j := range i.shards {
+		s := &i.shards[j]
+		s.mu.Lock()
+		for _, d := range s.samples {
+			w.latency.Observe(d)
+		}
+		s.mu.Unlock()
+	}
+	failures := i.failures.Load()
+	for n := range i.requests.Load() {
+		w.observe(n < failures)
+	}
+}
+
+func (w *window) observe(failure bool) {
+	if w.full && w.failures[w.next] {
+		w.errors--
+	}
+	w.failures[w.next] = failure
+	if failure {
+		w.errors++
+	}
+	w.next++
+	if w.next == len(w.failures) {
+		w.next = 0
+		w.full = true
+	}
+}
+
+func (w *window) count() int {
+	if w.full {
+		return len(w.failures)
+	}
+	return w.next
+}
+
+// errorRate - percentage of failures
+func (w *window) errorRate() float64 {
+	n := w.count()
+	if n == 0 {
+		return 0
+	}
+	return float64(w.errors) / float64(n) * 100
+}
@@ -0,0 +1,88 @@
+package probation
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+func ExampleGuard() {
+	cfg, p, ok := Guard(map[string]string{"timeout": "750ms", GuardKey: "5m", GuardErrorRateKey: "2"})
+	fmt.Printf("test: Guard() -> %v %+v [ok:%v]\n", cfg, p, ok)
+
+	cfg, _, ok = Guard(map[string]string{"timeout": "750ms"})
+	fmt.Printf("test: Guard() -> %v [ok:%v]\n", cfg, ok)
+
+	fmt.Printf("test: Failure() -> [200:%v] [404:%v] [503:%v]\n", Failure(&http.Response{StatusCode: http.StatusOK}, nil),
+		Failure(&http.Response{StatusCode: http.StatusNotFound}, nil), Failure(&http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+
+	//Output:
+	//test: Guard() -> map[timeout:750ms] {Window:5m0s ErrorRate:2 Latency:1.5 Minimum:20} [ok:true]
+	//test: Guard() -> map[timeout:750ms] [ok:false]
+	//test: Failure() -> [200:false] [404:false] [503:true]
+
+}
+
+func ExampleMonitor() {
+	m := NewMonitor(100)
+	// Evaluate each observation
+	m.interval = 0
+	ok := m.Start(Policy{Window: time.Minute, ErrorRate: 5, Latency: 1.5, Minimum: 20}, 2)
+	fmt.Printf("test: Start() -> [ok:%v] [active:%v]\n", ok, m.Active())
+
+	for i := 0; i < 50; i++ {
+		m.Observe(time.Millisecond*10, i%25 == 0)
+	}
+	m.Start(Policy{Window: time.Minute, ErrorRate: 5, Latency: 1.5, Minimum: 20}, 3)
+	var r Result
+	for i := 0; i < 20 && !r.Regressed; i++ {
+		r = m.Observe(time.Millisecond*10, i%4 == 0)
+	}
+	fmt.Printf("test: Observe() -> %+v [active:%v]\n", r, m.Active())
+
+	m.Start(Policy{Window: time.Minute, ErrorRate: 5, Latency: 1.5, Minimum: 20}, 4)
+	r = Result{}
+	for i := 0; i < 20 && !r.Regressed; i++ {
+		r = m.Observe(time.Millisecond*50, false)
+	}
+	fmt.Printf("test: Observe() -> %+v [active:%v]\n", r, m.Active())
+
+	m.Start(Policy{Window: time.Millisecond * 10, ErrorRate: 5, Latency: 1.5, Minimum: 20}, 5)
+	r = m.Observe(time.Millisecond*10, false)
+	fmt.Printf("test: Observe() -> %+v [active:%v]\n", r, m.Active())
+	time.Sleep(time.Millisecond * 20)
+	r = m.Observe(time.Millisecond*10, false)
+	fmt.Printf("test: Observe() -> %+v [active:%v]\n", r, m.Active())
+
+	//Output:
+	//test: Start() -> [ok:false] [active:false]
+	//test: Observe() -> {Passed:false Regressed:true Version:3 Reason:error rate [25.0%] baseline [4.0%]} [active:false]
+	//test: Observe() -> {Passed:false Regressed:true Version:4 Reason:latency p90 [50ms] baseline [10ms]} [active:false]
+	//test: Observe() -> {Passed:false Regressed:false Version:0 Reason:} [active:true]
+	//test: Observe() -> {Passed:true Regressed:false Version:5 Reason:} [active:false]
+
+}
+
+func ExampleMonitor_Observe() {
+	m := NewMonitor(1000)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Observe(time.Millisecond*10, false)
+			}
+		}()
+	}
+	wg.Wait()
+	// Outcomes of the current interval are added to the baseline when probation starts
+	fmt.Printf("test: Start() -> [minimum:801] [ok:%v]\n", m.Start(Policy{Window: time.Minute, Minimum: 801}, 1))
+	fmt.Printf("test: Start() -> [minimum:800] [ok:%v]\n", m.Start(Policy{Window: time.Minute, Minimum: 800}, 1))
+
+	//Output:
+	//test: Start() -> [minimum:801] [ok:false]
+	//test: Start() -> [minimum:800] [ok:true]
+
+}
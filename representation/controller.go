@@ -0,0 +1,140 @@
+package representation
+
+import (
+	"github.com/behavioral-ai/collective/operations"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/config"
+	"github.com/behavioral-ai/intermediary/latency"
+	"github.com/behavioral-ai/intermediary/probation"
+	"sync"
+	"time"
+)
+
+// Agent - an agent with a representation that is replaced, rather than modified, when configured
+type Agent interface {
+	Name() string
+	Update(m map[string]string)  // Apply a latest version configuration map to a copy of the representation
+	Restore(m map[string]string) // Replace the representation with a history version
+	Map() map[string]string      // Current representation as a configuration map
+}
+
+// Controller - configuration of an agent representation. Changes from messages, collective resolution, and
+// probation reverts are serialized, and each change is recorded in the history. A guarded change is on
+// probation, and the previous version is restored if request outcomes degrade.
+type Controller struct {
+	agent    Agent
+	schema   Schema
+	service  *operations.Service
+	resolver *Resolver
+
+	mu      sync.Mutex
+	history *config.History
+	monitor *probation.Monitor
+}
+
+// NewController - create a controller, the current representation of the agent is the initial history version
+func NewController(agent Agent, schema Schema, service *operations.Service) *Controller {
+	c := new(Controller)
+	c.agent = agent
+	c.schema = schema
+	c.service = service
+	c.resolver = NewResolver(schema.Migrate, schema.Fragments()...)
+	c.history = config.NewHistory(config.DefaultHistorySize, agent.Map())
+	c.monitor = probation.NewMonitor(latency.DefaultSize)
+	return c
+}
+
+// History - configuration history
+func (c *Controller) History() *config.History { return c.history }
+
+// Monitor - probation monitor
+func (c *Controller) Monitor() *probation.Monitor { return c.monitor }
+
+// Configure - apply a ConfigEvent map of any representation version, the sender is recorded as the source. A
+// guarded change is only on probation if enough requests were observed before the change.
+func (c *Controller) Configure(m *messaging.Message) *messaging.Status {
+	cfg, status := messaging.MapContent(m)
+	if !status.OK() {
+		return status
+	}
+	cfg, policy, guarded := probation.Guard(cfg)
+	cfg, err := c.schema.Migrate(cfg)
+	if err != nil {
+		return messaging.NewStatus(messaging.StatusInvalidArgument, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.agent.Update(cfg)
+	if e, ok := c.history.Add(m.From(), c.agent.Map()); ok {
+		if !guarded {
+			c.monitor.Stop()
+		} else if !c.monitor.Start(policy, e.Version-1) {
+			c.notify(probation.NewBaselineStatus(policy))
+		}
+	}
+	return messaging.StatusOK()
+}
+
+// Resolve - apply a changed representation from the collective resource repository, the current representation
+// is kept if resolution fails, and the failure is reported when requested
+func (c *Controller) Resolve(report bool) {
+	m, status := c.resolver.Resolve(c.agent.Name())
+	if !status.OK() {
+		if report {
+			c.notify(status)
+		}
+		return
+	}
+	if m == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.agent.Update(m)
+	if _, ok := c.history.Add(config.SourceResource, c.agent.Map()); ok {
+		c.monitor.Stop()
+	}
+}
+
+// Rollback - restore the version of a RollbackEvent, the restored configuration is recorded as a new version
+func (c *Controller) Rollback(m *messaging.Message) *messaging.Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, status := c.history.Target(m)
+	if !status.OK() {
+		return status
+	}
+	c.monitor.Stop()
+	c.agent.Restore(e.Config)
+	c.history.Add(config.RollbackSource(m), c.agent.Map())
+	return messaging.StatusOK()
+}
+
+// Observe - record a request outcome, a guarded change that degrades outcomes is reverted
+func (c *Controller) Observe(d time.Duration, failure bool) {
+	r := c.monitor.Observe(d, failure)
+	if !r.Regressed {
+		return
+	}
+	c.notify(probation.NewRevertStatus(r))
+	c.revert(r.Version)
+}
+
+// revert - restore the version before a guarded change, unless the change has since been replaced
+func (c *Controller) revert(version int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.history.Current(); !ok || e.Version != version+1 {
+		return
+	}
+	e, ok := c.history.Lookup(version)
+	if !ok {
+		return
+	}
+	c.agent.Restore(e.Config)
+	c.history.Add(probation.SourceProbation, c.agent.Map())
+}
+
+func (c *Controller) notify(status *messaging.Status) {
+	c.service.Message(messaging.NewStatusMessage(status.WithLocation(c.agent.Name()), c.agent.Name()))
+}
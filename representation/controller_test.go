@@ -0,0 +1,82 @@
+package representation
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/probation"
+	"maps"
+	"time"
+)
+
+type agent struct {
+	m map[string]string
+}
+
+func (a *agent) Name() string                { return "test:resiliency:agent/representation/request/http" }
+func (a *agent) Update(m map[string]string)  { a.m = maps.Clone(a.m); maps.Copy(a.m, m) }
+func (a *agent) Restore(m map[string]string) { a.m = maps.Clone(m) }
+func (a *agent) Map() map[string]string      { return a.m }
+func (a *agent) host() string                { return a.m["host"] }
+
+func ExampleController() {
+	a := &agent{m: map[string]string{"host": "localhost:8080"}}
+	c := NewController(a, Schema{{Fragment: "v1"}}, operationstest.NewService())
+
+	m := messaging.NewMapMessage(map[string]string{"host": "localhost:8081"})
+	m.SetFrom("operator")
+	status := c.Configure(m)
+	fmt.Printf("test: Configure() -> [host:%v] [status:%v]\n", a.host(), status.OK())
+
+	status = c.Configure(messaging.NewMapMessage(map[string]string{VersionKey: "v2"}))
+	fmt.Printf("test: Configure(\"v2\") -> [host:%v] [status:%v] [err:%v]\n", a.host(), status.OK(), status.Err)
+
+	status = c.Rollback(messaging.NewMapMessage(nil))
+	fmt.Printf("test: Rollback() -> [host:%v] [status:%v]\n", a.host(), status.OK())
+	for _, e := range c.History().Entries() {
+		fmt.Printf("test: Entries() -> [version:%v] [source:%v] [host:%v]\n", e.Version, e.Source, e.Config["host"])
+	}
+
+	//Output:
+	//test: Configure() -> [host:localhost:8081] [status:true]
+	//test: Configure("v2") -> [host:localhost:8081] [status:false] [err:invalid version [v2]]
+	//test: Rollback() -> [host:localhost:8080] [status:true]
+	//test: Entries() -> [version:1] [source:initial] [host:localhost:8080]
+	//test: Entries() -> [version:2] [source:operator] [host:localhost:8081]
+	//test: Entries() -> [version:3] [source:rollback] [host:localhost:8080]
+
+}
+
+func ExampleController_Observe() {
+	a := &agent{m: map[string]string{"host": "localhost:8080"}}
+	c := NewController(a, Schema{{Fragment: "v1"}}, operationstest.NewService())
+	observe := func(n int, failure bool) {
+		for i := 0; i < n; i++ {
+			c.Observe(time.Millisecond*10, failure)
+		}
+	}
+	// A guarded change without a baseline is not on probation
+	c.Configure(messaging.NewMapMessage(map[string]string{"host": "localhost:8081", probation.GuardKey: "5m"}))
+	fmt.Printf("test: Configure() -> [host:%v] [active:%v]\n", a.host(), c.Monitor().Active())
+	observe(50, false)
+
+	c.Configure(messaging.NewMapMessage(map[string]string{"host": "localhost:9090", probation.GuardKey: "5m"}))
+	fmt.Printf("test: Configure() -> [host:%v] [active:%v]\n", a.host(), c.Monitor().Active())
+	observe(20, true)
+	// Outcomes are evaluated once per interval
+	time.Sleep(time.Millisecond * 300)
+	observe(1, true)
+	fmt.Printf("test: Observe() -> [host:%v] [active:%v]\n", a.host(), c.Monitor().Active())
+
+	// A guarded change that is replaced while on probation is not reverted
+	c.Configure(messaging.NewMapMessage(map[string]string{"host": "localhost:9090", probation.GuardKey: "5m"}))
+	c.revert(c.History().Entries()[0].Version)
+	fmt.Printf("test: revert() -> [host:%v] [versions:%v]\n", a.host(), len(c.History().Entries()))
+
+	//Output:
+	//test: Configure() -> [host:localhost:8081] [active:false]
+	//test: Configure() -> [host:localhost:9090] [active:true]
+	//test: Observe() -> [host:localhost:8081] [active:false]
+	//test: revert() -> [host:localhost:9090] [versions:5]
+
+}
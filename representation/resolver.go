@@ -0,0 +1,56 @@
+package representation
+
+import (
+	"github.com/behavioral-ai/collective/resource"
+	"github.com/behavioral-ai/core/messaging"
+	"maps"
+)
+
+// Migrate - migrate a representation map to the version an agent applies
+type Migrate func(m map[string]string) (map[string]string, error)
+
+// Resolver - resolve an agent representation from the collective resource repository. Fragments are tried in
+// order, so the latest version is listed first.
+type Resolver struct {
+	fragments []string
+	migrate   Migrate
+	resolved  map[string]string
+}
+
+// NewResolver - create a resolver, a nil migrate applies the representation as resolved
+func NewResolver(migrate Migrate, fragments ...string) *Resolver {
+	r := new(Resolver)
+	r.fragments = fragments
+	r.migrate = migrate
+	return r
+}
+
+// Resolve - the migrated representation, or nil when it has not changed since the last resolution. The status is
+// not OK if resolution or migration fails, and the current representation should be kept.
+func (r *Resolver) Resolve(name string) (map[string]string, *messaging.Status) {
+	var (
+		m      map[string]string
+		status *messaging.Status
+	)
+	for _, fragment := range r.fragments {
+		m, status = resource.Resolve[map[string]string](name, fragment, resource.Resolver)
+		if status.OK() {
+			break
+		}
+	}
+	if status == nil || !status.OK() {
+		return nil, status
+	}
+	if maps.Equal(m, r.resolved) {
+		return nil, status
+	}
+	r.resolved = m
+	if r.migrate == nil {
+		return m, status
+	}
+	m, err := r.migrate(m)
+	if err != nil {
+		return nil, messaging.NewStatus(messaging.StatusInvalidArgument, err)
+	}
+	return m, status
+}
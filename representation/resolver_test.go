@@ -0,0 +1,34 @@
+package representation
+
+import (
+	"errors"
+	"fmt"
+	"github.com/behavioral-ai/collective/resource"
+)
+
+func ExampleResolver() {
+	name := "test:resiliency:agent/representation/request/http#resolver"
+	resource.NewAgent()
+	resource.Resolver.AddRepresentation(name, "v1", "author", map[string]string{"host": "localhost:8082"})
+
+	r := NewResolver(nil, "v2", "v1")
+	m, status := r.Resolve(name)
+	fmt.Printf("test: Resolve() -> %v [status:%v]\n", m, status.OK())
+
+	m, status = r.Resolve(name)
+	fmt.Printf("test: Resolve(unchanged) -> %v [status:%v]\n", m, status.OK())
+
+	r = NewResolver(func(m map[string]string) (map[string]string, error) { return nil, errors.New("invalid host") }, "v1")
+	m, status = r.Resolve(name)
+	fmt.Printf("test: Resolve(migrate) -> %v [status:%v] [err:%v]\n", m, status.OK(), status.Err)
+
+	_, status = r.Resolve(name + "-unknown")
+	fmt.Printf("test: Resolve(unknown) -> [status:%v]\n", status.OK())
+
+	//Output:
+	//test: Resolve() -> map[host:localhost:8082] [status:true]
+	//test: Resolve(unchanged) -> map[] [status:true]
+	//test: Resolve(migrate) -> map[] [status:false] [err:invalid host]
+	//test: Resolve(unknown) -> [status:false]
+
+}
@@ -0,0 +1,124 @@
+package representation
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	VersionKey = "version" // Representation version of a configuration map, a map without a version is the first version
+
+	listSeparator = "|"
+	nameSeparator = ":"
+)
+
+// Version - representation version, Upgrade converts a map of the previous version to this version, and Validate
+// checks a map of this version. The first version has no Upgrade.
+type Version struct {
+	Fragment string
+	Upgrade  func(m map[string]string) (map[string]string, error)
+	Validate func(m map[string]string) error
+}
+
+// Schema - representation versions, oldest first. A version is added by appending it with an upgrade from the
+// latest version.
+type Schema []Version
+
+// Latest - fragment of the latest version
+func (s Schema) Latest() string {
+	if len(s) == 0 {
+		return ""
+	}
+	return s[len(s)-1].Fragment
+}
+
+// Fragments - version fragments, latest first, the order of resolution
+func (s Schema) Fragments() []string {
+	var fragments []string
+	for i := len(s) - 1; i >= 0; i-- {
+		fragments = append(fragments, s[i].Fragment)
+	}
+	return fragments
+}
+
+// Migrate - validate a map of any version, and upgrade it to the latest version. The version of the returned map
+// is the latest version.
+func (s Schema) Migrate(m map[string]string) (map[string]string, error) {
+	i := 0
+	if v := m[VersionKey]; v != "" {
+		i = slices.IndexFunc(s, func(version Version) bool { return version.Fragment == v })
+		if i < 0 {
+			return nil, errors.New(fmt.Sprintf("invalid version [%v]", v))
+		}
+	}
+	c := Without(m, VersionKey)
+	if s[i].Validate != nil {
+		if err := s[i].Validate(c); err != nil {
+			return nil, err
+		}
+	}
+	for _, version := range s[i+1:] {
+		var err error
+		if c, err = version.Upgrade(c); err != nil {
+			return nil, err
+		}
+	}
+	c[VersionKey] = s.Latest()
+	return c, nil
+}
+
+// Field - member of a grouped key, the name in the grouped value and the key of the previous version
+type Field struct {
+	Name string
+	Key  string
+}
+
+// Group - replace the keys of the fields with a grouped key, "name:value|name:value", in field order
+func Group(m map[string]string, key string, fields []Field) {
+	var items []string
+	for _, f := range fields {
+		if v, ok := m[f.Key]; ok {
+			items = append(items, f.Name+nameSeparator+v)
+			delete(m, f.Key)
+		}
+	}
+	if len(items) > 0 {
+		m[key] = strings.Join(items, listSeparator)
+	}
+}
+
+// Ungroup - replace a grouped key with the keys of the fields, the map is not changed if an item is invalid
+func Ungroup(m map[string]string, key string, fields []Field) error {
+	s := m[key]
+	if s == "" {
+		delete(m, key)
+		return nil
+	}
+	values := make(map[string]string)
+	for _, item := range strings.Split(s, listSeparator) {
+		name, value, ok := strings.Cut(strings.TrimSpace(item), nameSeparator)
+		i := slices.IndexFunc(fields, func(f Field) bool { return f.Name == name })
+		if !ok || i < 0 {
+			return errors.New(fmt.Sprintf("invalid %v [%v]", key, item))
+		}
+		values[fields[i].Key] = value
+	}
+	delete(m, key)
+	maps.Copy(m, values)
+	return nil
+}
+
+// Without - copy of a map without keys
+func Without(m map[string]string, keys ...string) map[string]string {
+	c := maps.Clone(m)
+	if c == nil {
+		c = make(map[string]string)
+	}
+	for _, k := range keys {
+		delete(c, k)
+	}
+	return c
+}
@@ -0,0 +1,57 @@
+package representation
+
+import (
+	"fmt"
+)
+
+var (
+	fields = []Field{{"low", "low-ttl"}, {"high", "high-ttl"}}
+	schema = Schema{
+		{Fragment: "v1"},
+		{Fragment: "v2", Upgrade: func(m map[string]string) (map[string]string, error) {
+			Group(m, "ttl", fields)
+			return m, nil
+		}, Validate: func(m map[string]string) error {
+			return Ungroup(Without(m), "ttl", fields)
+		}},
+	}
+)
+
+func ExampleSchema_Migrate() {
+	m, err := schema.Migrate(map[string]string{"high-ttl": "1m", "low-ttl": "5s", "host": "localhost"})
+	fmt.Printf("test: Migrate(v1) -> %v [err:%v]\n", m, err)
+
+	m, err = schema.Migrate(m)
+	fmt.Printf("test: Migrate(v2) -> %v [err:%v]\n", m, err)
+
+	_, err = schema.Migrate(map[string]string{VersionKey: "v2", "ttl": "medium:1m"})
+	fmt.Printf("test: Migrate(v2) -> [err:%v]\n", err)
+
+	_, err = schema.Migrate(map[string]string{VersionKey: "v3"})
+	fmt.Printf("test: Migrate(v3) -> [err:%v]\n", err)
+
+	fmt.Printf("test: Latest() -> %v %v\n", schema.Latest(), schema.Fragments())
+
+	//Output:
+	//test: Migrate(v1) -> map[host:localhost ttl:low:5s|high:1m version:v2] [err:<nil>]
+	//test: Migrate(v2) -> map[host:localhost ttl:low:5s|high:1m version:v2] [err:<nil>]
+	//test: Migrate(v2) -> [err:invalid ttl [medium:1m]]
+	//test: Migrate(v3) -> [err:invalid version [v3]]
+	//test: Latest() -> v2 [v2 v1]
+
+}
+
+func ExampleUngroup() {
+	m := map[string]string{"ttl": "low:5s | high:1m", "host": "localhost"}
+	err := Ungroup(m, "ttl", fields)
+	fmt.Printf("test: Ungroup() -> %v [err:%v]\n", m, err)
+
+	m = map[string]string{"ttl": "low:5s|medium"}
+	err = Ungroup(m, "ttl", fields)
+	fmt.Printf("test: Ungroup() -> %v [err:%v]\n", m, err)
+
+	//Output:
+	//test: Ungroup() -> map[high-ttl:1m host:localhost low-ttl:5s] [err:<nil>]
+	//test: Ungroup() -> map[ttl:low:5s|medium] [err:invalid ttl [medium]]
+
+}
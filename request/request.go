@@ -1,15 +1,26 @@
 package request
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	access "github.com/behavioral-ai/core/access2"
 	"github.com/behavioral-ai/core/httpx"
 	"github.com/behavioral-ai/core/messaging"
 	"github.com/behavioral-ai/core/rest"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 )
 
+const (
+	XRequestBudget = "X-Request-Budget" // Remaining request time in milliseconds
+	contentLength  = "Content-Length"
+
+	StatusClientClosedRequest = 499
+)
+
 var (
 	serverErrorResponse = httpx.NewResponse(http.StatusInternalServerError, nil, nil)
 )
@@ -21,14 +32,38 @@ type Requester interface {
 	Do() rest.Exchange
 }
 
+// Do - request with no inbound context
 func Do(agent Requester, method string, url string, h http.Header, r io.ReadCloser) (resp *http.Response, status *messaging.Status) {
+	return DoWithContext(context.Background(), agent, method, url, h, r)
+}
+
+// DoWithContext - request derived from an inbound context, the timeout is the shorter of the agent timeout
+// and the context deadline. The remaining budget is propagated in the budget header of a copy of the header.
+func DoWithContext(ctx context.Context, agent Requester, method string, url string, h http.Header, r io.ReadCloser) (resp *http.Response, status *messaging.Status) {
 	start := time.Now().UTC()
-	req, err := http.NewRequest(method, url, r)
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timeout, err := Budget(ctx, agent.Timeout())
+	if err != nil {
+		return cancelled(err)
+	}
+	if h == nil {
+		h = make(http.Header)
+	} else {
+		h = h.Clone()
+	}
+	if timeout > 0 {
+		h.Set(XRequestBudget, strconv.FormatInt(timeout.Milliseconds(), 10))
+	} else {
+		h.Del(XRequestBudget)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
 	if err != nil {
 		return serverErrorResponse, messaging.NewStatus(messaging.StatusInvalidArgument, err)
 	}
 	req.Header = h
-	resp, err = httpx.ExchangeWithTimeout(agent.Timeout(), agent.Do())(req)
+	resp, err = httpx.ExchangeWithTimeout(timeout, agent.Do())(req)
 	if resp.Header == nil {
 		resp.Header = make(http.Header)
 	}
@@ -38,7 +73,85 @@ func Do(agent Requester, method string, url string, h http.Header, r io.ReadClos
 	}
 	status = messaging.StatusOK()
 	if agent.Log() {
-		access.Log(access.EgressTraffic, start, time.Since(start), agent.Route(), req, resp, access.Threshold{Timeout: agent.Timeout()})
+		access.Log(access.EgressTraffic, start, time.Since(start), agent.Route(), req, resp, access.Threshold{Timeout: timeout})
 	}
 	return
 }
+
+// Budget - shorter of the timeout and the context deadline. An error is returned if the context is done, or
+// there is no time remaining.
+func Budget(ctx context.Context, timeout time.Duration) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return 0, context.DeadlineExceeded
+		}
+		if timeout <= 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+	return timeout, nil
+}
+
+// WithBudget - request with a deadline from the inbound budget header, derived once at ingress so that
+// outbound budgets are computed from the context deadline. The cancel function releases the deadline.
+func WithBudget(r *http.Request) (*http.Request, context.CancelFunc) {
+	s := r.Header.Get(XRequestBudget)
+	if s == "" {
+		return r, func() {}
+	}
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return r, func() {}
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(ms)*time.Millisecond)
+	return r.WithContext(ctx), cancel
+}
+
+// CancelOnClose - defer a cancel function until the response body is closed, as the body is read after
+// the exchange returns
+func CancelOnClose(resp *http.Response, cancel context.CancelFunc) {
+	if resp == nil || resp.Body == nil || resp.Body == http.NoBody {
+		cancel()
+		return
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+func cancelled(err error) (*http.Response, *messaging.Status) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return httpx.NewResponse(http.StatusGatewayTimeout, nil, nil), messaging.NewStatus(http.StatusGatewayTimeout, err)
+	}
+	return httpx.NewResponse(StatusClientClosedRequest, nil, nil), messaging.NewStatus(StatusClientClosedRequest, err)
+}
+
+// SetBody - replace a response body with buffered content, updating the content length
+func SetBody(resp *http.Response, buf []byte) {
+	resp.Body = io.NopCloser(bytes.NewReader(buf))
+	resp.ContentLength = int64(len(buf))
+	if resp.Header != nil {
+		resp.Header.Set(contentLength, strconv.Itoa(len(buf)))
+	}
+}
+
+// Discard - read and close the body of a response that is not returned, so the connection can be reused
+func Discard(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
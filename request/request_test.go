@@ -1,12 +1,14 @@
 package request
 
 import (
+	"context"
 	"fmt"
 	"github.com/behavioral-ai/core/httpx"
 	"github.com/behavioral-ai/core/iox"
 	"github.com/behavioral-ai/core/messaging"
 	"github.com/behavioral-ai/core/rest"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -73,3 +75,70 @@ func ExampleDo_Get_Timeout() {
 	//test: Do() -> [resp:504] [status:Timeout [err:Get "https://www.google.com/search?q=golang": context deadline exceeded]]
 
 }
+
+// quietAgentT - agent with access logging disabled
+type quietAgentT struct {
+	agentT
+}
+
+func (a *quietAgentT) Log() bool { return false }
+
+func budgetExchange(r *http.Request) (*http.Response, error) {
+	select {
+	case <-time.After(time.Millisecond * 200):
+		h := make(http.Header)
+		h.Add(XRequestBudget, r.Header.Get(XRequestBudget))
+		return httpx.NewResponse(http.StatusOK, h, nil), nil
+	case <-r.Context().Done():
+		return httpx.NewResponse(http.StatusGatewayTimeout, nil, nil), r.Context().Err()
+	}
+}
+
+func ExampleDoWithContext() {
+	url := "http://localhost:8080/search?q=golang"
+	a := new(quietAgentT)
+	a.exchange = budgetExchange
+	a.timeout = time.Second * 2
+
+	// Agent timeout is used and propagated, the caller header is not updated
+	h := make(http.Header)
+	resp, status := DoWithContext(context.Background(), a, http.MethodGet, url, h, nil)
+	fmt.Printf("test: DoWithContext() -> [resp:%v] [status:%v] [budget:%v] [caller:%v]\n", resp.StatusCode, status.OK(), resp.Header.Get(XRequestBudget), h.Get(XRequestBudget))
+
+	// Budget header is shorter than the agent timeout, the deadline is derived at ingress
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Add(XRequestBudget, "100")
+	req, cancel0 := WithBudget(req)
+	defer cancel0()
+	resp, status = DoWithContext(req.Context(), a, http.MethodGet, url, req.Header, nil)
+	fmt.Printf("test: DoWithContext() -> [resp:%v] [status:%v] [caller:%v]\n", resp.StatusCode, status.OK(), req.Header.Get(XRequestBudget))
+
+	// Outbound budget is the remaining time to the deadline
+	req, _ = http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Add(XRequestBudget, "1000")
+	req, cancel1 := WithBudget(req)
+	defer cancel1()
+	resp, status = DoWithContext(req.Context(), a, http.MethodGet, url, req.Header, nil)
+	budget, _ := strconv.Atoi(resp.Header.Get(XRequestBudget))
+	fmt.Printf("test: DoWithContext() -> [resp:%v] [status:%v] [remaining:%v]\n", resp.StatusCode, status.OK(), budget > 0 && budget <= 1000)
+
+	// Inbound deadline is shorter than the agent timeout
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	resp, status = DoWithContext(ctx, a, http.MethodGet, url, make(http.Header), nil)
+	fmt.Printf("test: DoWithContext() -> [resp:%v] [status:%v]\n", resp.StatusCode, status.OK())
+
+	// Client has disconnected
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+	resp, status = DoWithContext(ctx2, a, http.MethodGet, url, make(http.Header), nil)
+	fmt.Printf("test: DoWithContext() -> [resp:%v] [status:%v]\n", resp.StatusCode, status.Err)
+
+	//Output:
+	//test: DoWithContext() -> [resp:200] [status:true] [budget:2000] [caller:]
+	//test: DoWithContext() -> [resp:504] [status:false] [caller:100]
+	//test: DoWithContext() -> [resp:200] [status:true] [remaining:true]
+	//test: DoWithContext() -> [resp:504] [status:false]
+	//test: DoWithContext() -> [resp:499] [status:context canceled]
+
+}
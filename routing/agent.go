@@ -9,10 +9,15 @@ import (
 	"github.com/behavioral-ai/core/httpx"
 	"github.com/behavioral-ai/core/messaging"
 	"github.com/behavioral-ai/core/rest"
-	"github.com/behavioral-ai/core/uri"
-	"github.com/behavioral-ai/intermediary/request"
-	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"github.com/behavioral-ai/intermediary/config"
+	"github.com/behavioral-ai/intermediary/latency"
+	"github.com/behavioral-ai/intermediary/namespace"
+	"github.com/behavioral-ai/intermediary/probation"
+	"github.com/behavioral-ai/intermediary/representation"
+	"github.com/behavioral-ai/intermediary/routing/representation2"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -26,35 +31,73 @@ var (
 )
 
 type agentT struct {
-	state   *representation1.Routing
+	name    string
+	state   atomic.Pointer[representation2.Routing]
+	running atomic.Bool
 	router  *rest.Router
 	service *operations.Service
 
-	review *messaging.Review
+	review      *messaging.Review
+	controller  *representation.Controller
+	mirrorStats mirrorStats
+	health      healthT
+	hedgeStats  hedgeStats
+	latency     *latency.Tracker
+	limitMu     sync.Mutex
+	limiters    map[string]*limiterT
+	ticker      *messaging.Ticker
+	emissary    *messaging.Channel
 }
 
 // init - register an agent constructor
 func init() {
-	repository.RegisterConstructor(NamespaceName, func() messaging.Agent {
-		return newAgent(representation1.Initialize(nil), nil, operations.Serve)
-	})
+	repository.RegisterConstructor(NamespaceName, Constructor)
+}
+
+// Constructor - create an agent with the default representation
+func Constructor() messaging.Agent {
+	return newAgent(representation2.Initialize(nil), nil, operations.Serve)
+}
+
+// NewInstance - create a named instance with the default representation
+func NewInstance(instance string) messaging.Agent {
+	a := newAgent(representation2.Initialize(nil), nil, operations.Serve)
+	a.name = namespace.Instance(NamespaceName, instance)
+	return a
 }
 
 func ConstructorOverride(m map[string]string, ex rest.Exchange, service *operations.Service) {
 	repository.RegisterConstructor(NamespaceName, func() messaging.Agent {
-		return newAgent(representation1.Initialize(m), ex, service)
+		return newAgent(representation2.Initialize(m), ex, service)
 	})
 }
 
-func newAgent(state *representation1.Routing, ex rest.Exchange, service *operations.Service) *agentT {
+// InstanceOverride - register a constructor for a named instance, each instance has its own representation
+func InstanceOverride(instance string, m map[string]string, ex rest.Exchange, service *operations.Service) {
+	name := namespace.Instance(NamespaceName, instance)
+	repository.RegisterConstructor(name, func() messaging.Agent {
+		a := newAgent(representation2.Initialize(m), ex, service)
+		a.name = name
+		return a
+	})
+}
+
+func newAgent(state *representation2.Routing, ex rest.Exchange, service *operations.Service) *agentT {
 	a := new(agentT)
-	a.state = state
+	a.name = NamespaceName
+	a.state.Store(state)
 	a.service = service
 	if ex == nil {
 		ex = httpx.Do
 	}
+	a.latency = latency.NewTracker(latency.DefaultSize)
+	a.controller = representation.NewController(a, representation2.Schema, service)
 	a.router = rest.NewRouter()
-	a.router.Modify(defaultRoute, a.state.AppHost, ex)
+	a.router.Modify(defaultRoute, a.state.Load().AppHost, ex)
+	a.router.Modify(mirrorRoute, a.state.Load().MirrorHost, ex)
+	a.router.Modify(canaryRoute, a.state.Load().Canary.Host, ex)
+	a.ticker = messaging.NewTicker(messaging.ChannelEmissary, a.state.Load().Interval)
+	a.emissary = messaging.NewEmissaryChannel()
 	return a
 }
 
@@ -62,22 +105,53 @@ func newAgent(state *representation1.Routing, ex rest.Exchange, service *operati
 func (a *agentT) String() string { return a.Name() }
 
 // Name - agent identifier
-func (a *agentT) Name() string { return NamespaceName }
+func (a *agentT) Name() string { return a.name }
 
 // Message - message the agent
 func (a *agentT) Message(m *messaging.Message) {
 	if m == nil {
 		return
 	}
-	if m.Name == messaging.ConfigEvent {
-		a.configure(m)
+	if !a.running.Load() {
+		if m.Name == messaging.ConfigEvent {
+			a.configure(m)
+			return
+		}
+		if m.Name == config.QueryEvent {
+			config.Reply(m, a.Map())
+			return
+		}
+		if m.Name == config.HistoryEvent {
+			config.ReplyHistory(m, a.controller.History())
+			return
+		}
+		if m.Name == config.RollbackEvent {
+			messaging.Reply(m, a.controller.Rollback(m), a.Name())
+			return
+		}
+		if m.Name == messaging.StartupEvent {
+			a.controller.Resolve(true)
+			a.run()
+			a.running.Store(true)
+			return
+		}
+		return
+	}
+	if m.Name == messaging.ShutdownEvent {
+		a.running.Store(false)
 	}
+	a.emissary.C <- m
+}
+
+// Run - run the agent
+func (a *agentT) run() {
+	go emissaryAttend(a)
 }
 
 // Log - implementation for Requester interface
-func (a *agentT) Log() bool              { return a.state.Log }
-func (a *agentT) Route() string          { return a.state.LogRouteName }
-func (a *agentT) Timeout() time.Duration { return a.state.Timeout }
+func (a *agentT) Log() bool              { return a.state.Load().Log }
+func (a *agentT) Route() string          { return a.state.Load().LogRouteName }
+func (a *agentT) Timeout() time.Duration { return a.timeout(a.state.Load().AppHost) }
 func (a *agentT) Do() rest.Exchange {
 	if rt, ok := a.router.Lookup(defaultRoute); ok {
 		return rt.Ex
@@ -87,22 +161,65 @@ func (a *agentT) Do() rest.Exchange {
 
 // Exchange - implementation for rest.Exchangeable interface
 func (a *agentT) Exchange(r *http.Request) (resp *http.Response, err error) {
+	state := a.state.Load()
 	rt, ok := a.router.Lookup(defaultRoute)
 	if !ok || rt != nil && rt.Uri == "" {
 		status := messaging.NewStatus(messaging.StatusInvalidArgument, errors.New("host configuration is empty")).WithLocation(a.Name())
 		a.service.Message(messaging.NewStatusMessage(status, a.Name()))
 		return serverErrorResponse, status.Err
 	}
-	var status *messaging.Status
-
-	url := uri.BuildURL(rt.Uri, r.URL.Path, r.URL.Query())
+	var (
+		status *messaging.Status
+		body   []byte
+		route  string
+	)
+	rt, route = a.selectRoute(r, rt)
+	mirror := a.mirrored(r)
+	hedge := rt.Name == defaultRoute && a.hedgeable(r)
+	failover := rt.Name == defaultRoute && a.failoverable(r)
+	if mirror || failover {
+		body, err = bufferBody(r)
+		if err != nil {
+			status = messaging.NewStatus(messaging.StatusIOError, err).WithLocation(a.Name())
+			a.service.Message(messaging.NewStatusMessage(status, a.Name()))
+			return serverErrorResponse, err
+		}
+	}
 	// TODO : need to check and remove Caching header.
-	resp, status = request.Do(a, r.Method, url, httpx.CloneHeaderWithEncoding(r), r.Body)
+	h := requestHeader(r, state.Forwarded, state.Request)
+	var h2 http.Header
+	if mirror {
+		h2 = h.Clone()
+	}
+	start := time.Now().UTC()
+	// A hedge that is not sent, with a single upstream or no hedging delay, is routed as if not hedged
+	sent := false
+	if hedge {
+		resp, status, sent = a.hedge(r, rt, h, body, failover)
+	}
+	switch {
+	case sent:
+	case failover:
+		resp, status = a.failover(r, rt, h, body)
+	default:
+		resp, status = a.do(r.Context(), route, rt.Uri, rt.Ex, r, h, r.Body)
+	}
+	a.controller.Observe(time.Since(start), probation.Failure(resp, status.Err))
+	if mirror {
+		go a.mirror(r, h2, body, resp.StatusCode, time.Since(start))
+	}
 	if status.Err != nil {
 		a.service.Message(messaging.NewStatusMessage(status.WithLocation(a.Name()), a.Name()))
 	}
+	if err = encode(r, resp, state.Compress); err != nil {
+		status = messaging.NewStatus(messaging.StatusIOError, err).WithLocation(a.Name())
+		a.service.Message(messaging.NewStatusMessage(status, a.Name()))
+		return serverErrorResponse, err
+	}
+	responseHeader(resp.Header, state.Response)
+	resp.Header.Set(XRouteName, route)
 	if resp.StatusCode == http.StatusGatewayTimeout {
-		resp.Header.Add(access2.XTimeout, fmt.Sprintf("%v", a.state.Timeout))
+		resp.Header.Add(access2.XTimeout, fmt.Sprintf("%v", a.timeout(rt.Uri)))
 	}
 	return resp, status.Err
 }
@@ -123,13 +240,10 @@ func (a *agentT) trace(task, observation, action string) {
 func (a *agentT) configure(m *messaging.Message) {
 	switch m.ContentType() {
 	case messaging.ContentTypeMap:
-		cfg, status := messaging.MapContent(m)
-		if !status.OK() {
+		if status := a.controller.Configure(m); !status.OK() {
 			messaging.Reply(m, status, a.Name())
 			return
 		}
-		a.state.Update(cfg)
-		a.router.Modify(defaultRoute, a.state.AppHost, nil)
 	case messaging.ContentTypeReview:
 		r, status := messaging.ReviewContent(m)
 		if !status.OK() {
@@ -141,6 +255,37 @@ func (a *agentT) configure(m *messaging.Message) {
 	messaging.Reply(m, messaging.StatusOK(), a.Name())
 }
 
+// Update - implementation for representation.Agent interface, a configuration map is applied to a copy of the
+// representation, and requests in progress keep the current representation
+func (a *agentT) Update(m map[string]string) {
+	r := representation2.Initialize(a.state.Load().Map())
+	r.Update(m)
+	a.replace(r)
+}
+
+// Restore - implementation for representation.Agent interface
+func (a *agentT) Restore(m map[string]string) {
+	a.replace(representation2.Initialize(m))
+}
+
+// replace - swap the representation, and the route hosts. Hosts that are no longer upstreams are not health checked.
+func (a *agentT) replace(r *representation2.Routing) {
+	a.state.Store(r)
+	a.router.Modify(defaultRoute, r.AppHost, nil)
+	a.router.Modify(mirrorRoute, r.MirrorHost, nil)
+	a.router.Modify(canaryRoute, r.Canary.Host, nil)
+	a.health.remove(func(host string) bool { return host == r.AppHost || r.Failover.Configured(host) })
+}
+
+// Map - implementation for representation.Agent interface
+func (a *agentT) Map() map[string]string { return a.state.Load().Map() }
+
+func (a *agentT) emissaryShutdown() {
+	a.emissary.Close()
+	a.ticker.Stop()
+	a.health.shutdown()
+}
+
 /*
 func (a *agentT) routerModify(uri string, ex rest.Exchange) {
 	a.router.Modify(defaultRoute, uri, ex)
@@ -5,13 +5,17 @@ import (
 	"github.com/behavioral-ai/collective/operations/operationstest"
 	"github.com/behavioral-ai/core/httpx"
 	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/latency"
+	"github.com/behavioral-ai/intermediary/probation"
 	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"github.com/behavioral-ai/intermediary/routing/representation2"
 	"net/http"
+	"sync/atomic"
 	"time"
 )
 
 func ExampleNew() {
-	a := newAgent(representation1.Initialize(nil), nil, operationstest.NewService())
+	a := newAgent(representation2.Initialize(nil), nil, operationstest.NewService())
 
 	fmt.Printf("test: newAgent() -> %v\n", a.Name())
 
@@ -30,7 +34,7 @@ func ExampleNew() {
 
 func ExampleExchange() {
 	url := "http://localhost:8080/search?q=golang"
-	a := newAgent(representation1.Initialize(nil), nil, operationstest.NewService())
+	a := newAgent(representation2.Initialize(nil), nil, operationstest.NewService())
 	ex := a.Exchange
 
 	req, _ := http.NewRequest(http.MethodGet, url, nil)
@@ -51,3 +55,82 @@ func ExampleExchange() {
 	//test: Exchange() -> [resp:200] [err:<nil>]
 
 }
+
+func Example_probation() {
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.AppHostKey: "localhost:8080",
+		representation1.LogKey:     "false",
+	}), func(r *http.Request) (*http.Response, error) {
+		if r.URL.Host == "localhost:9090" {
+			return httpx.NewResponse(http.StatusServiceUnavailable, nil, nil), nil
+		}
+		return httpx.NewResponse(http.StatusOK, nil, nil), nil
+	}, operationstest.NewService())
+	exchange := func(n int) {
+		for i := 0; i < n; i++ {
+			req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/search?q=golang", nil)
+			a.Exchange(req)
+		}
+	}
+	exchange(50)
+
+	m := messaging.NewMapMessage(map[string]string{
+		representation1.AppHostKey: "localhost:9090",
+		probation.GuardKey:         "5m",
+	})
+	m.SetFrom("operator")
+	a.Message(m)
+	fmt.Printf("test: Message() -> [app-host:%v] [active:%v]\n", a.state.Load().AppHost, a.controller.Monitor().Active())
+
+	exchange(20)
+	// Outcomes are evaluated once per interval
+	time.Sleep(time.Millisecond * 300)
+	exchange(1)
+	rt, _ := a.router.Lookup(defaultRoute)
+	fmt.Printf("test: Exchange() -> [app-host:%v] [uri:%v] [active:%v]\n", a.state.Load().AppHost, rt.Uri, a.controller.Monitor().Active())
+	for _, e := range a.controller.History().Entries() {
+		fmt.Printf("test: Entries() -> [version:%v] [source:%v] [routes:%v]\n", e.Version, e.Source, e.Config[representation2.RoutesKey])
+	}
+
+	//Output:
+	//test: Message() -> [app-host:localhost:9090] [active:true]
+	//test: Exchange() -> [app-host:localhost:8080] [uri:localhost:8080] [active:false]
+	//test: Entries() -> [version:1] [source:initial] [routes:app:localhost:8080]
+	//test: Entries() -> [version:2] [source:operator] [routes:app:localhost:9090]
+	//test: Entries() -> [version:3] [source:probation] [routes:app:localhost:8080]
+
+}
+
+func Example_adaptiveTimeout() {
+	var slow atomic.Bool
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.AppHostKey: "localhost:8080",
+		representation1.LogKey:     "false",
+		latency.TimeoutModeKey:     latency.ModeAdaptive,
+		latency.TimeoutMinKey:      "50ms",
+	}), func(r *http.Request) (*http.Response, error) {
+		if slow.Load() {
+			<-r.Context().Done()
+			return httpx.NewResponse(http.StatusGatewayTimeout, nil, nil), r.Context().Err()
+		}
+		return httpx.NewResponse(http.StatusOK, nil, nil), nil
+	}, operationstest.NewService())
+	exchange := func(n int) {
+		for i := 0; i < n; i++ {
+			req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/search?q=golang", nil)
+			a.Exchange(req)
+		}
+	}
+	exchange(20)
+	fmt.Printf("test: Exchange() -> [count:%v] [timeout:%v]\n", a.latency.Histogram("localhost:8080").Count(), a.timeout("localhost:8080"))
+
+	// Timed out requests are not observed, so the timeout does not climb
+	slow.Store(true)
+	exchange(5)
+	fmt.Printf("test: Exchange(timeout) -> [count:%v] [timeout:%v]\n", a.latency.Histogram("localhost:8080").Count(), a.timeout("localhost:8080"))
+
+	//Output:
+	//test: Exchange() -> [count:20] [timeout:50ms]
+	//test: Exchange(timeout) -> [count:20] [timeout:50ms]
+
+}
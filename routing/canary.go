@@ -0,0 +1,78 @@
+package routing
+
+import (
+	"github.com/behavioral-ai/core/rest"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	XRouteName         = "X-Route-Name"
+	canaryRoute        = "test:core:routing/canary"
+	canaryLogRouteName = "canary"
+)
+
+// selectRoute - select the default or canary route, and the route name used for logging
+func (a *agentT) selectRoute(r *http.Request, rt *rest.Route) (*rest.Route, string) {
+	if !a.canary(r) {
+		return rt, a.Route()
+	}
+	crt, ok := a.router.Lookup(canaryRoute)
+	if !ok || crt.Uri == "" {
+		return rt, a.Route()
+	}
+	return crt, canaryLogRouteName
+}
+
+// canary - determine canary assignment, header and cookie matches are assigned, otherwise a hash of the
+// client identifier provides a sticky percentage assignment
+func (a *agentT) canary(r *http.Request) bool {
+	c := a.state.Load().Canary
+	if c.Host == "" {
+		return false
+	}
+	if c.HeaderName != "" && c.HeaderValue != "" && r.Header.Get(c.HeaderName) == c.HeaderValue {
+		return true
+	}
+	if c.CookieName != "" && c.CookieValue != "" {
+		if cookie, err := r.Cookie(c.CookieName); err == nil && cookie.Value == c.CookieValue {
+			return true
+		}
+	}
+	if c.Percentage <= 0 {
+		return false
+	}
+	if c.Percentage >= 100 {
+		return true
+	}
+	return bucket(clientId(r, c.ClientKey)) < uint32(c.Percentage)
+}
+
+// clientId - client identifier from a header or cookie, defaulting to the originating address
+func clientId(r *http.Request, key string) string {
+	if key != "" {
+		if s := r.Header.Get(key); s != "" {
+			return s
+		}
+		if cookie, err := r.Cookie(key); err == nil {
+			return cookie.Value
+		}
+	}
+	if s := r.Header.Get(XForwardedFor); s != "" {
+		first, _, _ := strings.Cut(s, ",")
+		return strings.TrimSpace(first)
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// bucket - stable assignment of an identifier to one of 100 buckets
+func bucket(id string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return h.Sum32() % 100
+}
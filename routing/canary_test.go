@@ -0,0 +1,69 @@
+package routing
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"github.com/behavioral-ai/intermediary/routing/representation2"
+	"net/http"
+)
+
+func ExampleCanary() {
+	url := "http://localhost:8080/search?q=golang"
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.AppHostKey:      "primary:8080",
+		representation1.LogKey:          "false",
+		representation1.CanaryHostKey:   "canary:8080",
+		representation1.CanaryHeaderKey: "X-Canary:true",
+		representation1.CanaryCookieKey: "beta:opt-in",
+		representation1.CanaryClientKey: "X-User-Id",
+	}), hostExchange, operationstest.NewService())
+
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	resp, _ := a.Exchange(req)
+	fmt.Printf("test: Exchange() -> [resp:%v] [route:%v]\n", resp.StatusCode, resp.Header.Get(XRouteName))
+
+	req, _ = http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Add("X-Canary", "true")
+	resp, _ = a.Exchange(req)
+	fmt.Printf("test: Exchange() -> [header] [resp:%v] [route:%v]\n", resp.StatusCode, resp.Header.Get(XRouteName))
+
+	req, _ = http.NewRequest(http.MethodGet, url, nil)
+	req.AddCookie(&http.Cookie{Name: "beta", Value: "opt-in"})
+	resp, _ = a.Exchange(req)
+	fmt.Printf("test: Exchange() -> [cookie] [resp:%v] [route:%v]\n", resp.StatusCode, resp.Header.Get(XRouteName))
+
+	a.Message(messaging.NewMapMessage(map[string]string{representation1.CanaryPercentageKey: "50"}))
+	canary := 0
+	for i := 0; i < 1000; i++ {
+		req, _ = http.NewRequest(http.MethodGet, url, nil)
+		req.Header.Add("X-User-Id", fmt.Sprintf("user-%v", i))
+		if a.canary(req) {
+			canary++
+		}
+	}
+	req, _ = http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Add("X-User-Id", "user-1")
+	sticky := a.canary(req) == a.canary(req)
+	fmt.Printf("test: canary() -> [percentage:50] [assigned:%v] [sticky:%v]\n", canary > 400 && canary < 600, sticky)
+
+	a = newAgent(representation2.Initialize(map[string]string{
+		representation1.AppHostKey:      "primary:8080",
+		representation1.LogKey:          "false",
+		representation1.CanaryHostKey:   "canary:8080",
+		representation1.CanaryHeaderKey: "X-Canary",
+		representation1.CanaryCookieKey: "beta:",
+	}), hostExchange, operationstest.NewService())
+	req, _ = http.NewRequest(http.MethodGet, url, nil)
+	resp, _ = a.Exchange(req)
+	fmt.Printf("test: Exchange() -> [empty match] [resp:%v] [route:%v] [header:%v] [cookie:%v]\n", resp.StatusCode, resp.Header.Get(XRouteName), a.state.Load().Canary.HeaderName, a.state.Load().Canary.CookieName)
+
+	//Output:
+	//test: Exchange() -> [resp:200] [route:app]
+	//test: Exchange() -> [header] [resp:200] [route:canary]
+	//test: Exchange() -> [cookie] [resp:200] [route:canary]
+	//test: canary() -> [percentage:50] [assigned:true] [sticky:true]
+	//test: Exchange() -> [empty match] [resp:200] [route:app] [header:] [cookie:]
+
+}
@@ -0,0 +1,91 @@
+package routing
+
+import (
+	"github.com/behavioral-ai/intermediary/compression"
+	"github.com/behavioral-ai/intermediary/request"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	contentType  = "Content-Type"
+	contentRange = "Content-Range"
+	cacheControl = "Cache-Control"
+	noTransform  = "no-transform"
+)
+
+// encode - negotiate the response encoding with the client. Encoded upstream responses are decoded for clients
+// that do not accept the encoding, and when configured, uncompressed responses are compressed.
+func encode(r *http.Request, resp *http.Response, c representation1.Compress) error {
+	if resp == nil || resp.Body == nil || resp.Body == http.NoBody || r.Method == http.MethodHead {
+		return nil
+	}
+	// Partial content cannot be decoded or encoded independently of the full representation
+	if resp.Header.Get(contentRange) != "" {
+		return nil
+	}
+	accept := r.Header.Get(compression.AcceptEncoding)
+	encoding := resp.Header.Get(compression.ContentEncoding)
+	if encoding != "" && encoding != compression.IdentityEncoding {
+		if compression.Accepted(accept, encoding) || !compression.Supported(encoding) {
+			return nil
+		}
+		buf, err := readBody(resp)
+		if err != nil {
+			return err
+		}
+		buf, err = compression.Decode(buf, encoding)
+		if err != nil {
+			return err
+		}
+		resp.Header.Del(compression.ContentEncoding)
+		compression.SetETag(resp.Header, compression.IdentityEncoding)
+		compression.AddVary(resp.Header)
+		request.SetBody(resp, buf)
+		return nil
+	}
+	if !c.Enabled || resp.StatusCode != http.StatusOK || !compressible(resp.Header, c.Types) {
+		return nil
+	}
+	compression.AddVary(resp.Header)
+	encoding = compression.Negotiate(accept)
+	if encoding == "" || resp.ContentLength >= 0 && resp.ContentLength < int64(c.MinSize) {
+		return nil
+	}
+	buf, err := readBody(resp)
+	if err != nil {
+		return err
+	}
+	if len(buf) < c.MinSize {
+		request.SetBody(resp, buf)
+		return nil
+	}
+	buf, err = compression.Encode(buf, encoding)
+	if err != nil {
+		return err
+	}
+	resp.Header.Set(compression.ContentEncoding, encoding)
+	compression.SetETag(resp.Header, encoding)
+	request.SetBody(resp, buf)
+	return nil
+}
+
+// compressible - determine if the content type is allowed, and the upstream has not prohibited transformation
+func compressible(h http.Header, types []string) bool {
+	for _, v := range h.Values(cacheControl) {
+		for _, directive := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), noTransform) {
+				return false
+			}
+		}
+	}
+	return compression.Allowed(h.Get(contentType), types)
+}
+
+func readBody(resp *http.Response) ([]byte, error) {
+	buf, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	return buf, err
+}
@@ -0,0 +1,67 @@
+package routing
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/intermediary/compression"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"github.com/behavioral-ai/intermediary/routing/representation2"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var (
+	compressContent = strings.Repeat("the quick brown fox jumps over the lazy dog ", 50)
+)
+
+func encodedExchange(r *http.Request) (*http.Response, error) {
+	h := make(http.Header)
+	h.Set(contentType, "text/plain; charset=utf-8")
+	h.Set(compression.ETag, "\"v1\"")
+	if r.URL.Path == "/gzip" {
+		buf, _ := compression.Encode([]byte(compressContent), compression.GzipEncoding)
+		h.Set(compression.ContentEncoding, compression.GzipEncoding)
+		return httpx.NewResponse(http.StatusOK, h, buf), nil
+	}
+	if r.URL.Path == "/image" {
+		h.Set(contentType, "image/png")
+	}
+	return httpx.NewResponse(http.StatusOK, h, []byte(compressContent)), nil
+}
+
+func ExampleEncode() {
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.AppHostKey:       "localhost:8080",
+		representation1.LogKey:           "false",
+		representation1.CompressKey:      "true",
+		representation1.CompressTypesKey: "text/*|application/json",
+	}), encodedExchange, operationstest.NewService())
+
+	send := func(path, accept string) {
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080"+path, nil)
+		if accept != "" {
+			req.Header.Set(compression.AcceptEncoding, accept)
+		}
+		resp, err := a.Exchange(req)
+		buf, _ := io.ReadAll(resp.Body)
+		buf, _ = compression.Decode(buf, resp.Header.Get(compression.ContentEncoding))
+		fmt.Printf("test: Exchange(%v,%v) -> [encoding:%v] [vary:%v] [etag:%v] [smaller:%v] [equal:%v] [err:%v]\n", path, accept,
+			resp.Header.Get(compression.ContentEncoding), resp.Header.Get(compression.Vary), resp.Header.Get(compression.ETag), resp.ContentLength < int64(len(compressContent)),
+			string(buf) == compressContent, err)
+	}
+	send("/text", "")
+	send("/text", "br, deflate")
+	send("/image", "gzip")
+	send("/gzip", "gzip")
+	send("/gzip", "")
+
+	//Output:
+	//test: Exchange(/text,) -> [encoding:] [vary:Accept-Encoding] [etag:"v1"] [smaller:false] [equal:true] [err:<nil>]
+	//test: Exchange(/text,br, deflate) -> [encoding:deflate] [vary:Accept-Encoding] [etag:"v1-deflate"] [smaller:true] [equal:true] [err:<nil>]
+	//test: Exchange(/image,gzip) -> [encoding:] [vary:] [etag:"v1"] [smaller:false] [equal:true] [err:<nil>]
+	//test: Exchange(/gzip,gzip) -> [encoding:gzip] [vary:] [etag:"v1"] [smaller:true] [equal:true] [err:<nil>]
+	//test: Exchange(/gzip,) -> [encoding:] [vary:Accept-Encoding] [etag:W/"v1"] [smaller:false] [equal:true] [err:<nil>]
+
+}
@@ -0,0 +1,163 @@
+package routing
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/core/rest"
+	"github.com/behavioral-ai/core/uri"
+	"github.com/behavioral-ai/intermediary/request"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	healthLogRouteName = "health"
+)
+
+// healthT - hosts that have failed, and are waiting on a health check. Each check has a stop channel that is
+// closed when the host is removed from the representation, or when the agent shuts down.
+type healthT struct {
+	mu     sync.Mutex
+	closed bool
+	down   map[string]chan struct{}
+}
+
+func (h *healthT) isDown(host string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, ok := h.down[host]
+	return ok
+}
+
+// setDown - returns the stop channel of a new health check, if the host was up and the agent is running
+func (h *healthT) setDown(host string) (chan struct{}, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed || h.down[host] != nil {
+		return nil, false
+	}
+	if h.down == nil {
+		h.down = make(map[string]chan struct{})
+	}
+	stop := make(chan struct{})
+	h.down[host] = stop
+	return stop, true
+}
+
+// setUp - remove a host, unless it has since been marked down by a newer health check
+func (h *healthT) setUp(host string, stop chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.down[host] == stop {
+		delete(h.down, host)
+	}
+}
+
+// remove - stop the health check of hosts that are no longer upstreams
+func (h *healthT) remove(upstream func(host string) bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for host, stop := range h.down {
+		if !upstream(host) {
+			close(stop)
+			delete(h.down, host)
+		}
+	}
+}
+
+// shutdown - stop all health checks, no new checks are started
+func (h *healthT) shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for host, stop := range h.down {
+		close(stop)
+		delete(h.down, host)
+	}
+}
+
+// idempotent - methods that can be safely retried
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// failoverable - determine if a request can fail over
+func (a *agentT) failoverable(r *http.Request) bool {
+	return len(a.state.Load().Failover.Hosts) > 0 && idempotent(r.Method)
+}
+
+// upstreams - primary followed by the failover hosts, with hosts that are down moved to the end
+func (a *agentT) upstreams(primary string) []string {
+	var up, down []string
+	for _, host := range append([]string{primary}, a.state.Load().Failover.Hosts...) {
+		if a.health.isDown(host) {
+			down = append(down, host)
+		} else {
+			up = append(up, host)
+		}
+	}
+	return append(up, down...)
+}
+
+// failover - try each upstream in order until one succeeds, reporting each failover
+func (a *agentT) failover(r *http.Request, rt *rest.Route, h http.Header, body []byte) (*http.Response, *messaging.Status) {
+	hosts := a.upstreams(rt.Uri)
+	resp, status := a.do(r.Context(), a.Route(), hosts[0], rt.Ex, r, h, replayBody(body))
+	return a.failoverFrom(r, rt, h, body, hosts, resp, status)
+}
+
+// failoverFrom - given the response of the first host, try each remaining host in order until one succeeds
+func (a *agentT) failoverFrom(r *http.Request, rt *rest.Route, h http.Header, body []byte, hosts []string, resp *http.Response, status *messaging.Status) (*http.Response, *messaging.Status) {
+	for i := 1; ; i++ {
+		// A client disconnect or expired deadline is not an upstream failure
+		if r.Context().Err() != nil || !a.failed(resp, status) || i == len(hosts) {
+			return resp, status
+		}
+		// A host rejected by the concurrency limiter is overloaded, not down
+		if resp.Header.Get(XRejectReason) == "" {
+			a.markDown(hosts[i-1], rt.Ex)
+		}
+		failed := messaging.NewStatus(resp.StatusCode, fmt.Errorf("failover [%v] -> [%v]", hosts[i-1], hosts[i])).WithLocation(a.Name())
+		a.service.Message(messaging.NewStatusMessage(failed, a.Name()))
+		request.Discard(resp)
+		resp, status = a.do(r.Context(), a.Route(), hosts[i], rt.Ex, r, h, replayBody(body))
+	}
+}
+
+// failed - determine if a response is an upstream failure
+func (a *agentT) failed(resp *http.Response, status *messaging.Status) bool {
+	return status.Err != nil || a.state.Load().Failover.Failure(resp.StatusCode)
+}
+
+// markDown - mark a host as down, and health check until it is restored
+func (a *agentT) markDown(host string, ex rest.Exchange) {
+	if stop, ok := a.health.setDown(host); ok {
+		go a.healthCheck(host, ex, stop)
+	}
+}
+
+// healthCheck - probe a host until it is restored, removed from the representation, or the agent shuts down
+func (a *agentT) healthCheck(host string, ex rest.Exchange, stop chan struct{}) {
+	ticker := time.NewTicker(a.state.Load().Failover.HealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			state := a.state.Load()
+			resp, _ := request.Do(newRequester(healthLogRouteName, false, state.Timeout, ex), http.MethodGet, uri.BuildURL(host, state.Failover.HealthPath, nil), make(http.Header), nil)
+			request.Discard(resp)
+			if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+				a.health.setUp(host, stop)
+				a.trace(healthLogRouteName, fmt.Sprintf("host restored [%v]", host), "")
+				return
+			}
+		}
+	}
+}
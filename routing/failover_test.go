@@ -0,0 +1,105 @@
+package routing
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"github.com/behavioral-ai/intermediary/routing/representation2"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	primaryFailing atomic.Bool
+)
+
+func failoverExchange(r *http.Request) (*http.Response, error) {
+	if strings.HasPrefix(r.URL.Host, "primary") && primaryFailing.Load() {
+		return httpx.NewResponse(http.StatusServiceUnavailable, nil, nil), nil
+	}
+	h := make(http.Header)
+	h.Add("X-Host", r.URL.Host)
+	return httpx.NewResponse(http.StatusOK, h, nil), nil
+}
+
+func ExampleFailover() {
+	url := "http://localhost:8080/search?q=golang"
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.AppHostKey:        "primary:8080",
+		representation1.LogKey:            "false",
+		representation1.FailoverHostsKey:  "backup:8080|backup2:8080",
+		representation1.HealthIntervalKey: "50ms",
+	}), failoverExchange, operationstest.NewService())
+
+	primaryFailing.Store(true)
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	resp, err := a.Exchange(req)
+	fmt.Printf("test: Exchange() -> [resp:%v] [err:%v] [host:%v] [primary-down:%v]\n", resp.StatusCode, err, resp.Header.Get("X-Host"), a.health.isDown("primary:8080"))
+
+	req, _ = http.NewRequest(http.MethodPost, url, nil)
+	resp, err = a.Exchange(req)
+	fmt.Printf("test: Exchange() -> [method:%v] [resp:%v] [host:%v]\n", req.Method, resp.StatusCode, resp.Header.Get("X-Host"))
+
+	primaryFailing.Store(false)
+	time.Sleep(time.Millisecond * 200)
+	req, _ = http.NewRequest(http.MethodGet, url, nil)
+	resp, err = a.Exchange(req)
+	fmt.Printf("test: Exchange() -> [resp:%v] [err:%v] [host:%v] [primary-down:%v]\n", resp.StatusCode, err, resp.Header.Get("X-Host"), a.health.isDown("primary:8080"))
+
+	//Output:
+	//test: Exchange() -> [resp:200] [err:<nil>] [host:backup:8080] [primary-down:true]
+	//test: Exchange() -> [method:POST] [resp:503] [host:]
+	//test: Exchange() -> [resp:200] [err:<nil>] [host:primary:8080] [primary-down:false]
+
+}
+
+func ExampleFailover_healthCheckStop() {
+	var probes sync.Map
+	ex := func(r *http.Request) (*http.Response, error) {
+		count, _ := probes.LoadOrStore(r.URL.Host, new(atomic.Int64))
+		count.(*atomic.Int64).Add(1)
+		return httpx.NewResponse(http.StatusServiceUnavailable, nil, nil), nil
+	}
+	probed := func(host string) int64 {
+		if count, ok := probes.Load(host); ok {
+			return count.(*atomic.Int64).Load()
+		}
+		return 0
+	}
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.AppHostKey:        "primary:8080",
+		representation1.LogKey:            "false",
+		representation1.FailoverHostsKey:  "backup:8080|backup2:8080",
+		representation1.HealthIntervalKey: "20ms",
+	}), ex, operationstest.NewService())
+
+	a.markDown("backup:8080", ex)
+	a.markDown("backup2:8080", ex)
+	time.Sleep(time.Millisecond * 70)
+	fmt.Printf("test: markDown() -> [backup-probed:%v] [backup2-probed:%v]\n", probed("backup:8080") > 0, probed("backup2:8080") > 0)
+
+	// A host removed by reconfiguration is no longer health checked
+	a.Update(map[string]string{representation1.FailoverHostsKey: "backup2:8080"})
+	time.Sleep(time.Millisecond * 5)
+	backup, backup2 := probed("backup:8080"), probed("backup2:8080")
+	time.Sleep(time.Millisecond * 70)
+	fmt.Printf("test: Update() -> [backup-down:%v] [backup-probed:%v] [backup2-down:%v] [backup2-probed:%v]\n", a.health.isDown("backup:8080"), probed("backup:8080") > backup, a.health.isDown("backup2:8080"), probed("backup2:8080") > backup2)
+
+	// Health checks stop on shutdown, and are not started after
+	a.emissaryShutdown()
+	time.Sleep(time.Millisecond * 5)
+	backup2 = probed("backup2:8080")
+	a.markDown("primary:8080", ex)
+	time.Sleep(time.Millisecond * 70)
+	fmt.Printf("test: emissaryShutdown() -> [backup2-probed:%v] [primary-down:%v] [primary-probed:%v]\n", probed("backup2:8080") > backup2, a.health.isDown("primary:8080"), probed("primary:8080"))
+
+	//Output:
+	//test: markDown() -> [backup-probed:true] [backup2-probed:true]
+	//test: Update() -> [backup-down:false] [backup-probed:false] [backup2-down:true] [backup2-probed:true]
+	//test: emissaryShutdown() -> [backup2-probed:false] [primary-down:false] [primary-probed:0]
+
+}
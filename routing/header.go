@@ -0,0 +1,139 @@
+package routing
+
+import (
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	XForwardedFor   = "X-Forwarded-For"
+	XForwardedProto = "X-Forwarded-Proto"
+	XForwardedHost  = "X-Forwarded-Host"
+	Forwarded       = "Forwarded"
+	connection      = "Connection"
+	proxyPrefix     = "Proxy-"
+)
+
+// hopHeaders - headers that apply to a single connection, and are not forwarded, RFC 9110 7.6.1
+var hopHeaders = []string{
+	connection,
+	"Keep-Alive",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// requestHeader - create the upstream request header
+func requestHeader(r *http.Request, forwarded bool, rules representation1.Header) http.Header {
+	h := httpx.CloneHeaderWithEncoding(r)
+	if h == nil {
+		h = make(http.Header)
+	}
+	removeHopHeaders(h)
+	if forwarded {
+		addForwarded(h, r)
+	}
+	applyRules(h, rules)
+	return h
+}
+
+// responseHeader - update the upstream response header
+func responseHeader(h http.Header, rules representation1.Header) {
+	if h == nil {
+		return
+	}
+	removeHopHeaders(h)
+	applyRules(h, rules)
+}
+
+func removeHopHeaders(h http.Header) {
+	// Headers listed in Connection are also hop-by-hop
+	for _, v := range h.Values(connection) {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				h.Del(name)
+			}
+		}
+	}
+	for _, name := range hopHeaders {
+		h.Del(name)
+	}
+	for name := range h {
+		if strings.HasPrefix(name, proxyPrefix) {
+			delete(h, name)
+		}
+	}
+}
+
+func addForwarded(h http.Header, r *http.Request) {
+	proto := "http"
+	if r.TLS != nil {
+		proto = "https"
+	}
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if ip != "" {
+		if prior := h.Get(XForwardedFor); prior != "" {
+			h.Set(XForwardedFor, prior+", "+ip)
+		} else {
+			h.Set(XForwardedFor, ip)
+		}
+	}
+	if h.Get(XForwardedProto) == "" {
+		h.Set(XForwardedProto, proto)
+	}
+	if h.Get(XForwardedHost) == "" && r.Host != "" {
+		h.Set(XForwardedHost, r.Host)
+	}
+	var elems []string
+	if ip != "" {
+		if strings.Contains(ip, ":") {
+			ip = "\"[" + ip + "]\""
+		}
+		elems = append(elems, "for="+ip)
+	}
+	if r.Host != "" {
+		elems = append(elems, "host="+forwardedValue(r.Host))
+	}
+	elems = append(elems, "proto="+proto)
+	if prior := h.Get(Forwarded); prior != "" {
+		h.Set(Forwarded, prior+", "+strings.Join(elems, ";"))
+	} else {
+		h.Set(Forwarded, strings.Join(elems, ";"))
+	}
+}
+
+// forwardedValue - quote a Forwarded value that is not a token, a host with a port is not a token
+func forwardedValue(s string) string {
+	for _, c := range s {
+		if !isTokenChar(c) {
+			return "\"" + s + "\""
+		}
+	}
+	return s
+}
+
+func isTokenChar(c rune) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.ContainsRune("!#$%&'*+-.^_`|~", c)
+}
+
+func applyRules(h http.Header, rules representation1.Header) {
+	if rules.Empty() {
+		return
+	}
+	for _, name := range rules.Remove {
+		h.Del(name)
+	}
+	for name, values := range rules.Set {
+		h[name] = append([]string(nil), values...)
+	}
+	for name, values := range rules.Add {
+		h[name] = append(h[name], values...)
+	}
+}
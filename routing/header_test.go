@@ -0,0 +1,61 @@
+package routing
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"net/http"
+)
+
+func Example_requestHeader() {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/search?q=golang", nil)
+	req.RemoteAddr = "192.168.1.10:52345"
+	req.Header.Add("Connection", "keep-alive, X-Hop")
+	req.Header.Add("X-Hop", "true")
+	req.Header.Add("Keep-Alive", "timeout=5")
+	req.Header.Add("Proxy-Authorization", "Basic abc")
+	req.Header.Add(XForwardedFor, "10.0.0.1")
+	req.Header.Add("X-Debug", "true")
+
+	rules := representation1.Initialize(map[string]string{
+		representation1.RequestHeaderAddKey: "X-Tenant:orders",
+		representation1.RequestHeaderSetKey: "Cache-Control:no-cache",
+		representation1.RequestHeaderRmKey:  "x-debug",
+	}).Request
+	h := requestHeader(req, true, rules)
+	fmt.Printf("test: requestHeader() -> [connection:%v] [hop:%v] [keep-alive:%v] [proxy:%v] [debug:%v]\n", h.Get("Connection"), h.Get("X-Hop"), h.Get("Keep-Alive"), h.Get("Proxy-Authorization"), h.Get("X-Debug"))
+	fmt.Printf("test: requestHeader() -> [for:%v] [proto:%v] [host:%v]\n", h.Get(XForwardedFor), h.Get(XForwardedProto), h.Get(XForwardedHost))
+	fmt.Printf("test: requestHeader() -> [forwarded:%v]\n", h.Get(Forwarded))
+	fmt.Printf("test: requestHeader() -> [tenant:%v] [cache-control:%v]\n", h.Get("X-Tenant"), h.Get("Cache-Control"))
+
+	h = requestHeader(req, false, representation1.Header{})
+	fmt.Printf("test: requestHeader() -> [for:%v] [forwarded:%v] [debug:%v]\n", h.Get(XForwardedFor), h.Get(Forwarded), h.Get("X-Debug"))
+
+	//Output:
+	//test: requestHeader() -> [connection:] [hop:] [keep-alive:] [proxy:] [debug:]
+	//test: requestHeader() -> [for:10.0.0.1, 192.168.1.10] [proto:http] [host:localhost:8080]
+	//test: requestHeader() -> [forwarded:for=192.168.1.10;host="localhost:8080";proto=http]
+	//test: requestHeader() -> [tenant:orders] [cache-control:no-cache]
+	//test: requestHeader() -> [for:10.0.0.1] [forwarded:] [debug:true]
+
+}
+
+func Example_responseHeader() {
+	h := make(http.Header)
+	h.Add("Transfer-Encoding", "chunked")
+	h.Add("Upgrade", "h2c")
+	h.Add("Server", "nginx")
+	h.Add("X-Powered-By", "php")
+
+	rules := representation1.Initialize(map[string]string{
+		representation1.ResponseHeaderAddKey: "X-Frame-Options:DENY|Vary:Accept-Encoding",
+		representation1.ResponseHeaderRmKey:  "Server|X-Powered-By",
+	}).Response
+	responseHeader(h, rules)
+	fmt.Printf("test: responseHeader() -> [transfer-encoding:%v] [upgrade:%v] [server:%v] [powered-by:%v]\n", h.Get("Transfer-Encoding"), h.Get("Upgrade"), h.Get("Server"), h.Get("X-Powered-By"))
+	fmt.Printf("test: responseHeader() -> [frame-options:%v] [vary:%v]\n", h.Get("X-Frame-Options"), h.Get("Vary"))
+
+	//Output:
+	//test: responseHeader() -> [transfer-encoding:] [upgrade:] [server:] [powered-by:]
+	//test: responseHeader() -> [frame-options:DENY] [vary:Accept-Encoding]
+
+}
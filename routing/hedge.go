@@ -0,0 +1,124 @@
+package routing
+
+import (
+	"context"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/core/rest"
+	"github.com/behavioral-ai/intermediary/request"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	hedgeLogRouteName = "hedge"
+)
+
+// hedgeStats - hedging budget accounting
+type hedgeStats struct {
+	Requests atomic.Int64
+	Hedged   atomic.Int64
+}
+
+type hedgeResult struct {
+	index  int
+	resp   *http.Response
+	status *messaging.Status
+}
+
+// hedgeable - determine if a request can be hedged
+func (a *agentT) hedgeable(r *http.Request) bool {
+	return a.state.Load().Hedge.Percentile > 0 && r.Method == http.MethodGet
+}
+
+// hedgeDelay - delay before a hedged request is sent, false if there are not enough observations
+func (a *agentT) hedgeDelay(host string) (time.Duration, bool) {
+	state := a.state.Load()
+	d, ok := a.latency.Histogram(host).Percentile(float64(state.Hedge.Percentile))
+	if !ok {
+		return 0, false
+	}
+	return max(d, state.Hedge.MinDelay), true
+}
+
+// reserveHedge - count a hedged request toward the budget, false if the budget is spent
+func (a *agentT) reserveHedge() bool {
+	for {
+		hedged := a.hedgeStats.Hedged.Load()
+		if hedged*100 >= a.hedgeStats.Requests.Load()*int64(a.state.Load().Hedge.Budget) {
+			return false
+		}
+		if a.hedgeStats.Hedged.CompareAndSwap(hedged, hedged+1) {
+			return true
+		}
+	}
+}
+
+// hedge - send the request to the primary upstream, and if there is no response within the hedging
+// delay, also send it to the next upstream. The first successful response is returned and the other
+// request is cancelled. A request is only hedged when it is sent, within the budget. When failover is
+// enabled, a failed response fails over to the upstreams that were not sent the request. Returns false
+// if the request was not sent, as there is a single upstream or no hedging delay.
+func (a *agentT) hedge(r *http.Request, rt *rest.Route, h http.Header, body []byte, failover bool) (*http.Response, *messaging.Status, bool) {
+	a.hedgeStats.Requests.Add(1)
+	hosts := a.upstreams(rt.Uri)
+	if len(hosts) < 2 {
+		return nil, nil, false
+	}
+	delay, ok := a.hedgeDelay(rt.Uri)
+	if !ok {
+		return nil, nil, false
+	}
+	var cancel [2]context.CancelFunc
+	results := make(chan hedgeResult, 2)
+	send := func(index int, host, route string, h http.Header) {
+		var ctx context.Context
+		ctx, cancel[index] = context.WithCancel(r.Context())
+		go func() {
+			resp, status := a.do(ctx, route, host, rt.Ex, r, h, nil)
+			results <- hedgeResult{index: index, resp: resp, status: status}
+		}()
+	}
+	// Headers are updated when sent, so the hedged request needs a copy
+	h2 := h.Clone()
+	send(0, hosts[0], a.Route(), h)
+	// fail - return the response of the last upstream sent, or fail over to the upstreams after it
+	fail := func(res hedgeResult, sent int) (*http.Response, *messaging.Status, bool) {
+		if !failover {
+			return res.resp, res.status, true
+		}
+		resp, status := a.failoverFrom(r, rt, h2, body, hosts[sent-1:], res.resp, res.status)
+		return resp, status, true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case res := <-results:
+		return fail(res, 1)
+	case <-r.Context().Done():
+		res := <-results
+		return res.resp, res.status, true
+	case <-timer.C:
+	}
+	if !a.reserveHedge() {
+		return fail(<-results, 1)
+	}
+	send(1, hosts[1], hedgeLogRouteName, h2)
+
+	first := <-results
+	if !a.failed(first.resp, first.status) {
+		cancel[1-first.index]()
+		go func() {
+			request.Discard((<-results).resp)
+		}()
+		return first.resp, first.status, true
+	}
+	// Both upstreams failed, failover continues after the hedged upstream
+	primary, hedged := first, <-results
+	if primary.index == 1 {
+		primary, hedged = hedged, primary
+	}
+	request.Discard(primary.resp)
+	return fail(hedged, 2)
+}
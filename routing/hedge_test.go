@@ -0,0 +1,122 @@
+package routing
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"github.com/behavioral-ai/intermediary/routing/representation2"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	primaryCancelled atomic.Bool
+)
+
+func slowExchange(r *http.Request) (*http.Response, error) {
+	if strings.HasPrefix(r.URL.Host, "primary") {
+		select {
+		case <-time.After(time.Millisecond * 300):
+		case <-r.Context().Done():
+			primaryCancelled.Store(true)
+			return httpx.NewResponse(http.StatusGatewayTimeout, nil, nil), r.Context().Err()
+		}
+	}
+	h := make(http.Header)
+	h.Add("X-Host", r.URL.Host)
+	return httpx.NewResponse(http.StatusOK, h, nil), nil
+}
+
+func ExampleHedge() {
+	url := "http://localhost:8080/search?q=golang"
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.AppHostKey:         "primary:8080",
+		representation1.LogKey:             "false",
+		representation1.FailoverHostsKey:   "secondary:8080",
+		representation1.HedgePercentileKey: "95",
+		representation1.HedgeBudgetKey:     "100",
+	}), slowExchange, operationstest.NewService())
+
+	// Not enough observations to determine a hedging delay
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	resp, _ := a.Exchange(req)
+	fmt.Printf("test: Exchange() -> [resp:%v] [host:%v] [hedged:%v]\n", resp.StatusCode, resp.Header.Get("X-Host"), a.hedgeStats.Hedged.Load())
+
+	for i := 0; i < 50; i++ {
+		a.latency.Observe("primary:8080", time.Millisecond)
+	}
+	req, _ = http.NewRequest(http.MethodGet, url, nil)
+	resp, _ = a.Exchange(req)
+	time.Sleep(time.Millisecond * 50)
+	fmt.Printf("test: Exchange() -> [resp:%v] [host:%v] [route:%v] [hedged:%v] [cancelled:%v]\n", resp.StatusCode, resp.Header.Get("X-Host"), resp.Header.Get(XRouteName), a.hedgeStats.Hedged.Load(), primaryCancelled.Load())
+
+	// Budget spent, the request waits for the primary and is not counted as hedged
+	a.state.Load().Hedge.Budget = 10
+	req, _ = http.NewRequest(http.MethodGet, url, nil)
+	resp, _ = a.Exchange(req)
+	fmt.Printf("test: Exchange() -> [budget:10] [resp:%v] [host:%v] [hedged:%v] [requests:%v]\n", resp.StatusCode, resp.Header.Get("X-Host"), a.hedgeStats.Hedged.Load(), a.hedgeStats.Requests.Load())
+
+	// A single upstream is not hedged, the alternate would be the primary
+	a = newAgent(representation2.Initialize(map[string]string{
+		representation1.AppHostKey:         "primary:8080",
+		representation1.LogKey:             "false",
+		representation1.HedgePercentileKey: "95",
+		representation1.HedgeBudgetKey:     "100",
+	}), slowExchange, operationstest.NewService())
+	for i := 0; i < 50; i++ {
+		a.latency.Observe("primary:8080", time.Millisecond)
+	}
+	req, _ = http.NewRequest(http.MethodGet, url, nil)
+	resp, _ = a.Exchange(req)
+	fmt.Printf("test: Exchange() -> [single upstream] [resp:%v] [host:%v] [hedged:%v]\n", resp.StatusCode, resp.Header.Get("X-Host"), a.hedgeStats.Hedged.Load())
+
+	//Output:
+	//test: Exchange() -> [resp:200] [host:primary:8080] [hedged:0]
+	//test: Exchange() -> [resp:200] [host:secondary:8080] [route:app] [hedged:1] [cancelled:true]
+	//test: Exchange() -> [budget:10] [resp:200] [host:primary:8080] [hedged:1] [requests:3]
+	//test: Exchange() -> [single upstream] [resp:200] [host:primary:8080] [hedged:0]
+
+}
+
+func fastFailExchange(r *http.Request) (*http.Response, error) {
+	if strings.HasPrefix(r.URL.Host, "primary") {
+		return httpx.NewResponse(http.StatusServiceUnavailable, nil, nil), nil
+	}
+	h := make(http.Header)
+	h.Add("X-Host", r.URL.Host)
+	return httpx.NewResponse(http.StatusOK, h, nil), nil
+}
+
+func Example_hedgeFailover() {
+	url := "http://localhost:8080/search?q=golang"
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.AppHostKey:         "primary:8080",
+		representation1.LogKey:             "false",
+		representation1.FailoverHostsKey:   "secondary:8080",
+		representation1.HealthIntervalKey:  "1h",
+		representation1.HedgePercentileKey: "95",
+		representation1.HedgeBudgetKey:     "100",
+	}), fastFailExchange, operationstest.NewService())
+
+	// Not enough observations to determine a hedging delay, the request fails over
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	resp, _ := a.Exchange(req)
+	fmt.Printf("test: Exchange() -> [resp:%v] [host:%v] [primary-down:%v] [hedged:%v]\n", resp.StatusCode, resp.Header.Get("X-Host"), a.health.isDown("primary:8080"), a.hedgeStats.Hedged.Load())
+
+	// The primary fails before the hedging delay, the request fails over without a hedge
+	a.health.remove(func(host string) bool { return host != "primary:8080" })
+	for i := 0; i < 50; i++ {
+		a.latency.Observe("primary:8080", time.Millisecond*100)
+	}
+	req, _ = http.NewRequest(http.MethodGet, url, nil)
+	resp, _ = a.Exchange(req)
+	fmt.Printf("test: Exchange() -> [resp:%v] [host:%v] [primary-down:%v] [hedged:%v]\n", resp.StatusCode, resp.Header.Get("X-Host"), a.health.isDown("primary:8080"), a.hedgeStats.Hedged.Load())
+
+	//Output:
+	//test: Exchange() -> [resp:200] [host:secondary:8080] [primary-down:true] [hedged:0]
+	//test: Exchange() -> [resp:200] [host:secondary:8080] [primary-down:true] [hedged:0]
+
+}
@@ -0,0 +1,134 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	XRejectReason   = "X-Reject-Reason"
+	ReasonLimit     = "concurrency-limit"
+	ReasonQueueWait = "queue-timeout"
+	limitTask       = "concurrency-limit"
+	limitBackoff    = 0.9
+)
+
+// limiterT - AIMD concurrency limit for an upstream host
+type limiterT struct {
+	mu       sync.Mutex
+	limit    float64
+	inflight int
+	waiters  []chan struct{} // Queued requests, first in first out, a waiter is closed when it is granted a slot
+}
+
+func newLimiter(initial int) *limiterT {
+	l := new(limiterT)
+	l.limit = float64(max(initial, 1))
+	return l
+}
+
+// acquire - acquire, waiting in the queue if configured, returns a reason if rejected. Queued requests are
+// granted slots in arrival order, and a new request does not bypass the queue.
+func (l *limiterT) acquire(ctx context.Context, cfg representation1.Limit) (string, bool) {
+	l.mu.Lock()
+	if len(l.waiters) == 0 && l.inflight < int(l.limit) {
+		l.inflight++
+		l.mu.Unlock()
+		return "", true
+	}
+	if cfg.QueueWait <= 0 || len(l.waiters) >= cfg.Queue {
+		l.mu.Unlock()
+		return ReasonLimit, false
+	}
+	ready := make(chan struct{})
+	l.waiters = append(l.waiters, ready)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(cfg.QueueWait)
+	defer timer.Stop()
+	select {
+	case <-ready:
+		return "", true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if i := slices.Index(l.waiters, ready); i >= 0 {
+		l.waiters = slices.Delete(l.waiters, i, i+1)
+	} else {
+		// Granted a slot as the wait ended, so pass it to the next waiter
+		l.inflight--
+		l.grant()
+	}
+	return ReasonQueueWait, false
+}
+
+// grant - grant available slots to queued requests, the caller holds the lock
+func (l *limiterT) grant() {
+	for len(l.waiters) > 0 && l.inflight < int(l.limit) {
+		l.inflight++
+		close(l.waiters[0])
+		l.waiters = slices.Delete(l.waiters, 0, 1)
+	}
+}
+
+// release - release and adjust the limit, the limit is decreased on failure or when latency exceeds
+// the threshold, otherwise increased. Returns the previous and current limit.
+func (l *limiterT) release(cfg representation1.Limit, latency time.Duration, failed bool) (prev, curr int) {
+	l.mu.Lock()
+	prev = int(l.limit)
+	l.inflight--
+	if failed || cfg.Latency > 0 && latency > cfg.Latency {
+		l.limit *= limitBackoff
+	} else if l.inflight+1 >= prev {
+		// Only increase when the limit was reached, an idle upstream does not need a higher limit
+		l.limit += 1 / l.limit
+	}
+	l.limit = min(max(l.limit, float64(max(cfg.Min, 1))), float64(max(cfg.Max, cfg.Min, 1)))
+	curr = int(l.limit)
+	l.grant()
+	l.mu.Unlock()
+	return
+}
+
+// limiter - limiter for an upstream host, created on first use
+func (a *agentT) limiter(host string) *limiterT {
+	a.limitMu.Lock()
+	defer a.limitMu.Unlock()
+	if a.limiters == nil {
+		a.limiters = make(map[string]*limiterT)
+	}
+	l, ok := a.limiters[host]
+	if !ok {
+		l = newLimiter(a.state.Load().Limit.Initial)
+		a.limiters[host] = l
+	}
+	return l
+}
+
+// limitFailure - response indicates the upstream is overloaded
+func limitFailure(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusGatewayTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests
+}
+
+// limitReport - report a limit change to operations
+func (a *agentT) limitReport(host string, prev, curr int) {
+	if prev == curr {
+		return
+	}
+	a.service.Trace(a.Name(), limitTask, fmt.Sprintf("host [%v] limit [%v] -> [%v]", host, prev, curr), "")
+}
+
+func rejectResponse(reason string) *http.Response {
+	h := make(http.Header)
+	h.Set(XRejectReason, reason)
+	return httpx.NewResponse(http.StatusServiceUnavailable, h, nil)
+}
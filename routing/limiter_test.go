@@ -0,0 +1,121 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"github.com/behavioral-ai/intermediary/routing/representation2"
+	"net/http"
+	"time"
+)
+
+func Example_limiter() {
+	cfg := representation1.Initialize(map[string]string{
+		representation1.LimitKey:        representation1.LimitAIMD,
+		representation1.LimitInitialKey: "10",
+		representation1.LimitMinKey:     "2",
+		representation1.LimitMaxKey:     "11",
+		representation1.LimitLatencyKey: "100ms",
+	}).Limit
+	l := newLimiter(cfg.Initial)
+	for i := 0; i < 10; i++ {
+		_, ok := l.acquire(context.Background(), cfg)
+		if !ok {
+			fmt.Printf("test: acquire() -> [inflight:%v] [ok:%v]\n", l.inflight, ok)
+		}
+	}
+	reason, ok := l.acquire(context.Background(), cfg)
+	fmt.Printf("test: acquire() -> [inflight:%v] [reason:%v] [ok:%v]\n", l.inflight, reason, ok)
+
+	prev, curr := l.release(cfg, time.Millisecond*10, false)
+	fmt.Printf("test: release() -> [success] [prev:%v] [curr:%v] [limit:%.2f]\n", prev, curr, l.limit)
+
+	prev, curr = l.release(cfg, time.Millisecond*500, false)
+	fmt.Printf("test: release() -> [latency] [prev:%v] [curr:%v] [limit:%.2f]\n", prev, curr, l.limit)
+
+	prev, curr = l.release(cfg, time.Millisecond*10, true)
+	fmt.Printf("test: release() -> [failure] [prev:%v] [curr:%v] [limit:%.2f]\n", prev, curr, l.limit)
+
+	//Output:
+	//test: acquire() -> [inflight:10] [reason:concurrency-limit] [ok:false]
+	//test: release() -> [success] [prev:10] [curr:10] [limit:10.10]
+	//test: release() -> [latency] [prev:10] [curr:9] [limit:9.09]
+	//test: release() -> [failure] [prev:9] [curr:8] [limit:8.18]
+
+}
+
+func Example_limiterExchange() {
+	url := "http://localhost:8080/search?q=golang"
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.AppHostKey:        "localhost:8080",
+		representation1.LogKey:            "false",
+		representation1.LimitKey:          representation1.LimitAIMD,
+		representation1.LimitInitialKey:   "1",
+		representation1.LimitQueueKey:     "1",
+		representation1.LimitQueueWaitKey: "500ms",
+	}), func(r *http.Request) (*http.Response, error) {
+		time.Sleep(time.Millisecond * 100)
+		return httpx.NewResponse(http.StatusOK, nil, nil), nil
+	}, operationstest.NewService())
+
+	results := make(chan string, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			resp, _ := a.Exchange(req)
+			results <- fmt.Sprintf("%v %v", resp.StatusCode, resp.Header.Get(XRejectReason))
+		}()
+		time.Sleep(time.Millisecond * 10)
+	}
+	counts := make(map[string]int)
+	for i := 0; i < 3; i++ {
+		counts[<-results]++
+	}
+	// One request is sent, one waits in the queue, and one is rejected
+	fmt.Printf("test: Exchange() -> [ok:%v] [rejected:%v]\n", counts["200 "], counts["503 "+ReasonLimit])
+
+	//Output:
+	//test: Exchange() -> [ok:2] [rejected:1]
+
+}
+
+func Example_limiterQueue() {
+	cfg := representation1.Initialize(map[string]string{
+		representation1.LimitKey:          representation1.LimitAIMD,
+		representation1.LimitInitialKey:   "1",
+		representation1.LimitMaxKey:       "1",
+		representation1.LimitQueueKey:     "3",
+		representation1.LimitQueueWaitKey: "1s",
+	}).Limit
+	l := newLimiter(cfg.Initial)
+	l.acquire(context.Background(), cfg)
+
+	// Every release is granted to a queued request, in arrival order
+	order := make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			if _, ok := l.acquire(context.Background(), cfg); ok {
+				order <- i
+			}
+		}()
+		time.Sleep(time.Millisecond * 10)
+	}
+	var granted []int
+	for i := 0; i < 3; i++ {
+		l.release(cfg, time.Millisecond, false)
+		granted = append(granted, <-order)
+	}
+	fmt.Printf("test: acquire() -> [granted:%v] [inflight:%v] [waiters:%v]\n", granted, l.inflight, len(l.waiters))
+
+	// A queued request that times out leaves the queue
+	cfg.QueueWait = time.Millisecond * 10
+	reason, ok := l.acquire(context.Background(), cfg)
+	fmt.Printf("test: acquire() -> [reason:%v] [ok:%v] [inflight:%v] [waiters:%v]\n", reason, ok, l.inflight, len(l.waiters))
+
+	//Output:
+	//test: acquire() -> [granted:[0 1 2]] [inflight:1] [waiters:0]
+	//test: acquire() -> [reason:queue-timeout] [ok:false] [inflight:1] [waiters:0]
+
+}
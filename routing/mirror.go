@@ -0,0 +1,77 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"github.com/behavioral-ai/intermediary/request"
+	"math/rand/v2"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	mirrorRoute        = "test:core:routing/mirror"
+	mirrorLogRouteName = "mirror"
+	mirrorPublishCount = 100 // Mirrored requests between publishing the comparison
+)
+
+// mirrorStats - comparison of primary and mirror responses
+type mirrorStats struct {
+	Count          atomic.Int64
+	StatusMismatch atomic.Int64
+	PrimaryLatency atomic.Int64 // Cumulative nanoseconds
+	MirrorLatency  atomic.Int64 // Cumulative nanoseconds
+}
+
+func (s *mirrorStats) record(primaryCode, mirrorCode int, primary, mirror time.Duration) {
+	s.Count.Add(1)
+	if primaryCode != mirrorCode {
+		s.StatusMismatch.Add(1)
+	}
+	s.PrimaryLatency.Add(int64(primary))
+	s.MirrorLatency.Add(int64(mirror))
+}
+
+// mirrored - determine if a request is selected for mirroring, only configured methods are mirrored
+func (a *agentT) mirrored(r *http.Request) bool {
+	state := a.state.Load()
+	if state.MirrorHost == "" || state.MirrorPercentage <= 0 || !state.Mirrored(r.Method) {
+		return false
+	}
+	return rand.IntN(100) < state.MirrorPercentage
+}
+
+// mirror - fire-and-forget request to the mirror host, the response is discarded after being compared
+// to the primary
+func (a *agentT) mirror(r *http.Request, h http.Header, body []byte, primaryCode int, primary time.Duration) {
+	rt, ok := a.router.Lookup(mirrorRoute)
+	if !ok || rt.Uri == "" {
+		return
+	}
+	start := time.Now().UTC()
+	// The mirror is independent of the inbound request, which may complete first
+	resp, _ := a.do(context.Background(), mirrorLogRouteName, rt.Uri, rt.Ex, r, h, replayBody(body))
+	elapsed := time.Since(start)
+	request.Discard(resp)
+	a.mirrorStats.record(primaryCode, resp.StatusCode, primary, elapsed)
+	if resp.StatusCode != primaryCode {
+		a.trace(mirrorLogRouteName, fmt.Sprintf("status mismatch [primary:%v] [mirror:%v]", primaryCode, resp.StatusCode), "")
+	}
+	if a.mirrorStats.Count.Load() >= mirrorPublishCount {
+		a.publishMirror()
+	}
+}
+
+// publishMirror - publish the comparison of primary and mirror responses, and reset the counts
+func (a *agentT) publishMirror() {
+	count := a.mirrorStats.Count.Swap(0)
+	mismatch := a.mirrorStats.StatusMismatch.Swap(0)
+	primary := a.mirrorStats.PrimaryLatency.Swap(0)
+	mirror := a.mirrorStats.MirrorLatency.Swap(0)
+	if count <= 0 {
+		return
+	}
+	a.service.Trace(a.Name(), mirrorLogRouteName, fmt.Sprintf("mirrored [%v] status mismatch [%v] mean latency [primary:%v] [mirror:%v]",
+		count, mismatch, time.Duration(primary/count), time.Duration(mirror/count)), "")
+}
@@ -0,0 +1,63 @@
+package routing
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"github.com/behavioral-ai/intermediary/routing/representation2"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func hostExchange(r *http.Request) (*http.Response, error) {
+	if strings.HasPrefix(r.URL.Host, "mirror") {
+		return httpx.NewResponse(http.StatusInternalServerError, nil, nil), nil
+	}
+	return httpx.NewResponse(http.StatusOK, nil, nil), nil
+}
+
+func Example_mirror() {
+	url := "http://localhost:8080/search?q=golang"
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.AppHostKey:          "primary:8080",
+		representation1.LogKey:              "false",
+		representation1.MirrorHostKey:       "mirror:8080",
+		representation1.MirrorPercentageKey: "100",
+	}), hostExchange, operationstest.NewService())
+
+	// Unsafe methods are not mirrored by default
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader("request content"))
+	resp, err := a.Exchange(req)
+	time.Sleep(time.Millisecond * 100)
+	fmt.Printf("test: Exchange(POST) -> [resp:%v] [err:%v] [mirrored:%v] [mismatch:%v]\n", resp.StatusCode, err, a.mirrorStats.Count.Load(), a.mirrorStats.StatusMismatch.Load())
+
+	req, _ = http.NewRequest(http.MethodGet, url, nil)
+	resp, err = a.Exchange(req)
+	time.Sleep(time.Millisecond * 100)
+	fmt.Printf("test: Exchange(GET) -> [resp:%v] [err:%v] [mirrored:%v] [mismatch:%v]\n", resp.StatusCode, err, a.mirrorStats.Count.Load(), a.mirrorStats.StatusMismatch.Load())
+
+	a.state.Load().MirrorPercentage = 0
+	req, _ = http.NewRequest(http.MethodGet, url, nil)
+	resp, err = a.Exchange(req)
+	time.Sleep(time.Millisecond * 100)
+	fmt.Printf("test: Exchange(GET) -> [resp:%v] [err:%v] [mirrored:%v] [mismatch:%v]\n", resp.StatusCode, err, a.mirrorStats.Count.Load(), a.mirrorStats.StatusMismatch.Load())
+
+	a.publishMirror()
+	fmt.Printf("test: publishMirror() -> [mirrored:%v] [mismatch:%v]\n", a.mirrorStats.Count.Load(), a.mirrorStats.StatusMismatch.Load())
+
+	a.state.Load().Update(map[string]string{representation1.MirrorPercentageKey: "100", representation1.MirrorMethodsKey: "get|post"})
+	req, _ = http.NewRequest(http.MethodPost, url, strings.NewReader("request content"))
+	resp, err = a.Exchange(req)
+	time.Sleep(time.Millisecond * 100)
+	fmt.Printf("test: Exchange(POST) -> [resp:%v] [err:%v] [mirrored:%v] [methods:%v]\n", resp.StatusCode, err, a.mirrorStats.Count.Load(), a.state.Load().MirrorMethods)
+
+	//Output:
+	//test: Exchange(POST) -> [resp:200] [err:<nil>] [mirrored:0] [mismatch:0]
+	//test: Exchange(GET) -> [resp:200] [err:<nil>] [mirrored:1] [mismatch:1]
+	//test: Exchange(GET) -> [resp:200] [err:<nil>] [mirrored:1] [mismatch:1]
+	//test: publishMirror() -> [mirrored:0] [mismatch:0]
+	//test: Exchange(POST) -> [resp:200] [err:<nil>] [mirrored:1] [methods:[GET POST]]
+
+}
@@ -0,0 +1,72 @@
+package representation1
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Canary - canary traffic split, a header or cookie match takes precedence over the percentage
+type Canary struct {
+	Host        string
+	Percentage  int    // Percentage of clients assigned to the canary
+	HeaderName  string // Header match
+	HeaderValue string
+	CookieName  string // Cookie match
+	CookieValue string
+	ClientKey   string // Header or cookie identifying a client for sticky assignment, remote address if empty
+}
+
+func formatCanary(c Canary, m map[string]string) {
+	if c.Host != "" {
+		m[CanaryHostKey] = c.Host
+	}
+	if c.HeaderName != "" {
+		m[CanaryHeaderKey] = c.HeaderName + valueSeparator + c.HeaderValue
+	}
+	if c.CookieName != "" {
+		m[CanaryCookieKey] = c.CookieName + valueSeparator + c.CookieValue
+	}
+	if c.ClientKey != "" {
+		m[CanaryClientKey] = c.ClientKey
+	}
+	m[CanaryPercentageKey] = strconv.Itoa(c.Percentage)
+}
+
+func parseCanary(c *Canary, m map[string]string) {
+	s := m[CanaryHostKey]
+	if s != "" {
+		c.Host = s
+	}
+	s = m[CanaryHeaderKey]
+	if s != "" {
+		if name, value, ok := parseMatch(s); ok {
+			c.HeaderName, c.HeaderValue = name, value
+		}
+	}
+	s = m[CanaryCookieKey]
+	if s != "" {
+		if name, value, ok := parseMatch(s); ok {
+			c.CookieName, c.CookieValue = name, value
+		}
+	}
+	s = m[CanaryClientKey]
+	if s != "" {
+		c.ClientKey = s
+	}
+	s = m[CanaryPercentageKey]
+	if s != "" {
+		pct, err := parsePercentage(s)
+		if err != nil {
+			return
+		}
+		c.Percentage = pct
+	}
+}
+
+// parseMatch - parse "name:value", false if the name or value is empty, as an empty value would match every
+// request without the header or cookie
+func parseMatch(s string) (name, value string, ok bool) {
+	name, value, _ = strings.Cut(s, valueSeparator)
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	return name, value, name != "" && value != ""
+}
@@ -0,0 +1,47 @@
+package representation1
+
+import (
+	"github.com/behavioral-ai/intermediary/config"
+	"strconv"
+)
+
+const (
+	defaultCompressMinSize = 1024
+)
+
+var (
+	defaultCompressTypes = []string{"text/*", "application/json", "application/javascript", "application/xml", "image/svg+xml"}
+)
+
+// Compress - response compression configuration, upstream responses are always decoded for clients that
+// do not accept the upstream encoding
+type Compress struct {
+	Enabled bool     // Compress uncompressed upstream responses
+	Types   []string // Content type allowlist, entries may use a subtype wildcard, "text/*"
+	MinSize int      // Minimum content length in bytes
+}
+
+func initCompress(c *Compress) {
+	c.Types = defaultCompressTypes
+	c.MinSize = defaultCompressMinSize
+}
+
+func formatCompress(c Compress, m map[string]string) {
+	m[CompressKey] = config.FormatBool(c.Enabled)
+	if len(c.Types) > 0 {
+		m[CompressTypesKey] = config.FormatList(c.Types)
+	}
+	m[CompressMinSizeKey] = strconv.Itoa(c.MinSize)
+}
+
+func parseCompress(c *Compress, m map[string]string) {
+	s := m[CompressKey]
+	if s != "" {
+		c.Enabled = s == "true"
+	}
+	s = m[CompressTypesKey]
+	if s != "" {
+		c.Types = parseList(s)
+	}
+	parseCount(&c.MinSize, m[CompressMinSizeKey])
+}
@@ -0,0 +1,106 @@
+package representation1
+
+import (
+	"github.com/behavioral-ai/core/fmtx"
+	"github.com/behavioral-ai/intermediary/config"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHealthPath     = "/health"
+	defaultHealthInterval = time.Second * 10
+)
+
+// Failover - ordered failover hosts, and the health check used to restore a failed host
+type Failover struct {
+	Hosts          []string
+	StatusCodes    []int // Response status codes that trigger failover, in addition to connection failures
+	HealthPath     string
+	HealthInterval time.Duration
+}
+
+func initFailover(f *Failover) {
+	f.StatusCodes = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	f.HealthPath = defaultHealthPath
+	f.HealthInterval = defaultHealthInterval
+}
+
+// Failure - determine if a response status code triggers failover
+func (f Failover) Failure(code int) bool {
+	for _, c := range f.StatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Configured - determine if a host is in the failover configuration
+func (f Failover) Configured(host string) bool {
+	for _, h := range f.Hosts {
+		if h == host {
+			return true
+		}
+	}
+	return false
+}
+
+func formatFailover(f Failover, m map[string]string) {
+	if len(f.Hosts) > 0 {
+		m[FailoverHostsKey] = config.FormatList(f.Hosts)
+	}
+	if len(f.StatusCodes) > 0 {
+		var codes []string
+		for _, code := range f.StatusCodes {
+			codes = append(codes, strconv.Itoa(code))
+		}
+		m[FailoverStatusKey] = config.FormatList(codes)
+	}
+	m[HealthPathKey] = f.HealthPath
+	m[HealthIntervalKey] = config.FormatDuration(f.HealthInterval)
+}
+
+func parseFailover(f *Failover, m map[string]string) {
+	s := m[FailoverHostsKey]
+	if s != "" {
+		f.Hosts = parseList(s)
+	}
+	s = m[HealthPathKey]
+	if s != "" {
+		f.HealthPath = s
+	}
+	s = m[FailoverStatusKey]
+	if s != "" {
+		var codes []int
+		for _, t := range parseList(s) {
+			code, err := strconv.Atoi(t)
+			if err != nil {
+				return
+			}
+			codes = append(codes, code)
+		}
+		f.StatusCodes = codes
+	}
+	s = m[HealthIntervalKey]
+	if s != "" {
+		dur, err := fmtx.ParseDuration(s)
+		if err != nil || dur <= 0 {
+			return
+		}
+		f.HealthInterval = dur
+	}
+}
+
+// parseList - parse "item|item"
+func parseList(s string) []string {
+	var items []string
+	for _, t := range strings.Split(s, ruleSeparator) {
+		if t = strings.TrimSpace(t); t != "" {
+			items = append(items, t)
+		}
+	}
+	return items
+}
@@ -0,0 +1,84 @@
+package representation1
+
+import (
+	"github.com/behavioral-ai/intermediary/config"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	ruleSeparator  = "|"
+	valueSeparator = ":"
+)
+
+// Header - header rewrite rules, applied in remove, set, add order
+type Header struct {
+	Add    http.Header
+	Set    http.Header
+	Remove []string
+}
+
+// Empty - determine if there are no rules
+func (h Header) Empty() bool {
+	return len(h.Add) == 0 && len(h.Set) == 0 && len(h.Remove) == 0
+}
+
+// parseHeaderValues - parse "name:value|name:value"
+func parseHeaderValues(s string) http.Header {
+	h := make(http.Header)
+	for _, rule := range strings.Split(s, ruleSeparator) {
+		name, value, ok := strings.Cut(rule, valueSeparator)
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		h.Add(name, strings.TrimSpace(value))
+	}
+	return h
+}
+
+// parseHeaderNames - parse "name|name"
+func parseHeaderNames(s string) []string {
+	var names []string
+	for _, name := range parseList(s) {
+		names = append(names, http.CanonicalHeaderKey(name))
+	}
+	return names
+}
+
+// formatHeaderValues - format "name:value|name:value", sorted by name
+func formatHeaderValues(h http.Header) string {
+	var rules []string
+	for _, name := range slices.Sorted(maps.Keys(h)) {
+		for _, value := range h[name] {
+			rules = append(rules, name+valueSeparator+value)
+		}
+	}
+	return config.FormatList(rules)
+}
+
+func formatHeader(h Header, m map[string]string, add, set, remove string) {
+	if len(h.Add) > 0 {
+		m[add] = formatHeaderValues(h.Add)
+	}
+	if len(h.Set) > 0 {
+		m[set] = formatHeaderValues(h.Set)
+	}
+	if len(h.Remove) > 0 {
+		m[remove] = config.FormatList(h.Remove)
+	}
+}
+
+func parseHeader(h *Header, add, set, remove string) {
+	if add != "" {
+		h.Add = parseHeaderValues(add)
+	}
+	if set != "" {
+		h.Set = parseHeaderValues(set)
+	}
+	if remove != "" {
+		h.Remove = parseHeaderNames(remove)
+	}
+}
@@ -0,0 +1,58 @@
+package representation1
+
+import (
+	"github.com/behavioral-ai/core/fmtx"
+	"github.com/behavioral-ai/intermediary/config"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultHedgeBudget   = 10
+	defaultHedgeMinDelay = time.Millisecond * 10
+)
+
+// Hedge - hedged request configuration, a zero percentile disables hedging
+type Hedge struct {
+	Percentile int           // Observed latency percentile used as the hedging delay
+	Budget     int           // Maximum percentage of requests that are hedged
+	MinDelay   time.Duration // Lower bound on the hedging delay
+}
+
+func initHedge(h *Hedge) {
+	h.Budget = defaultHedgeBudget
+	h.MinDelay = defaultHedgeMinDelay
+}
+
+func formatHedge(h Hedge, m map[string]string) {
+	m[HedgePercentileKey] = strconv.Itoa(h.Percentile)
+	m[HedgeBudgetKey] = strconv.Itoa(h.Budget)
+	m[HedgeMinDelayKey] = config.FormatDuration(h.MinDelay)
+}
+
+func parseHedge(h *Hedge, m map[string]string) {
+	s := m[HedgePercentileKey]
+	if s != "" {
+		pct, err := parsePercentage(s)
+		if err != nil {
+			return
+		}
+		h.Percentile = pct
+	}
+	s = m[HedgeBudgetKey]
+	if s != "" {
+		pct, err := parsePercentage(s)
+		if err != nil {
+			return
+		}
+		h.Budget = pct
+	}
+	s = m[HedgeMinDelayKey]
+	if s != "" {
+		dur, err := fmtx.ParseDuration(s)
+		if err != nil {
+			return
+		}
+		h.MinDelay = dur
+	}
+}
@@ -0,0 +1,90 @@
+package representation1
+
+import (
+	"github.com/behavioral-ai/core/fmtx"
+	"github.com/behavioral-ai/intermediary/config"
+	"strconv"
+	"time"
+)
+
+const (
+	LimitNone = "none"
+	LimitAIMD = "aimd" // Additive increase, multiplicative decrease
+
+	defaultLimitInitial = 20
+	defaultLimitMin     = 1
+	defaultLimitMax     = 200
+)
+
+// Limit - concurrency limit on outstanding requests per upstream host
+type Limit struct {
+	Mode      string
+	Initial   int
+	Min       int
+	Max       int
+	Latency   time.Duration // Latency above which the limit is decreased, zero to only decrease on failures
+	Queue     int           // Maximum requests waiting when the limit is reached
+	QueueWait time.Duration // Maximum wait in the queue
+}
+
+func initLimit(l *Limit) {
+	l.Mode = LimitNone
+	l.Initial = defaultLimitInitial
+	l.Min = defaultLimitMin
+	l.Max = defaultLimitMax
+}
+
+// Enabled - determine if requests are limited
+func (l Limit) Enabled() bool {
+	return l.Mode == LimitAIMD
+}
+
+func formatLimit(l Limit, m map[string]string) {
+	m[LimitKey] = l.Mode
+	m[LimitInitialKey] = strconv.Itoa(l.Initial)
+	m[LimitMinKey] = strconv.Itoa(l.Min)
+	m[LimitMaxKey] = strconv.Itoa(l.Max)
+	m[LimitLatencyKey] = config.FormatDuration(l.Latency)
+	m[LimitQueueKey] = strconv.Itoa(l.Queue)
+	m[LimitQueueWaitKey] = config.FormatDuration(l.QueueWait)
+}
+
+func parseLimit(l *Limit, m map[string]string) {
+	s := m[LimitKey]
+	if s == LimitNone || s == LimitAIMD {
+		l.Mode = s
+	}
+	if !parseCount(&l.Initial, m[LimitInitialKey]) || !parseCount(&l.Min, m[LimitMinKey]) ||
+		!parseCount(&l.Max, m[LimitMaxKey]) || !parseCount(&l.Queue, m[LimitQueueKey]) {
+		return
+	}
+	s = m[LimitLatencyKey]
+	if s != "" {
+		dur, err := fmtx.ParseDuration(s)
+		if err != nil {
+			return
+		}
+		l.Latency = dur
+	}
+	s = m[LimitQueueWaitKey]
+	if s != "" {
+		dur, err := fmtx.ParseDuration(s)
+		if err != nil {
+			return
+		}
+		l.QueueWait = dur
+	}
+}
+
+// parseCount - parse a non-negative integer, an empty string leaves the value unchanged
+func parseCount(v *int, s string) bool {
+	if s == "" {
+		return true
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 0 {
+		return false
+	}
+	*v = i
+	return true
+}
@@ -0,0 +1,95 @@
+package representation1
+
+import (
+	"errors"
+	"fmt"
+	"github.com/behavioral-ai/intermediary/config"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	querySeparator = "="
+)
+
+// Rewrite - upstream path and query rewrite rules
+type Rewrite struct {
+	StripPrefix string
+	AddPrefix   string
+	Pattern     *regexp.Regexp
+	Replace     string // Regexp replacement, with ${n} capture group expansion
+	QueryAdd    url.Values
+	QueryRemove []string
+}
+
+// Empty - determine if there are no rules
+func (r Rewrite) Empty() bool {
+	return r.StripPrefix == "" && r.AddPrefix == "" && r.Pattern == nil && len(r.QueryAdd) == 0 && len(r.QueryRemove) == 0
+}
+
+func formatRewrite(r Rewrite, m map[string]string) {
+	if r.StripPrefix != "" {
+		m[PathStripPrefixKey] = r.StripPrefix
+	}
+	if r.AddPrefix != "" {
+		m[PathAddPrefixKey] = r.AddPrefix
+	}
+	if r.Pattern != nil {
+		m[PathPatternKey] = r.Pattern.String()
+		m[PathReplaceKey] = r.Replace
+	}
+	if len(r.QueryAdd) > 0 {
+		var rules []string
+		for _, name := range slices.Sorted(maps.Keys(r.QueryAdd)) {
+			for _, value := range r.QueryAdd[name] {
+				rules = append(rules, name+querySeparator+value)
+			}
+		}
+		m[QueryAddKey] = config.FormatList(rules)
+	}
+	if len(r.QueryRemove) > 0 {
+		m[QueryRemoveKey] = config.FormatList(r.QueryRemove)
+	}
+}
+
+// parseRewrite - an invalid path pattern is not applied, and the other rules are parsed
+func parseRewrite(r *Rewrite, m map[string]string) error {
+	var err error
+	s := m[PathStripPrefixKey]
+	if s != "" {
+		r.StripPrefix = s
+	}
+	s = m[PathAddPrefixKey]
+	if s != "" {
+		r.AddPrefix = s
+	}
+	s = m[PathPatternKey]
+	if s != "" {
+		if re, compileErr := regexp.Compile(s); compileErr != nil {
+			err = errors.New(fmt.Sprintf("invalid %v [%v]", PathPatternKey, s))
+		} else {
+			r.Pattern = re
+			r.Replace = m[PathReplaceKey]
+		}
+	}
+	s = m[QueryAddKey]
+	if s != "" {
+		r.QueryAdd = make(url.Values)
+		for _, rule := range strings.Split(s, ruleSeparator) {
+			name, value, ok := strings.Cut(rule, querySeparator)
+			name = strings.TrimSpace(name)
+			if !ok || name == "" {
+				continue
+			}
+			r.QueryAdd.Add(name, strings.TrimSpace(value))
+		}
+	}
+	s = m[QueryRemoveKey]
+	if s != "" {
+		r.QueryRemove = parseList(s)
+	}
+	return err
+}
@@ -2,6 +2,12 @@ package representation1
 
 import (
 	"github.com/behavioral-ai/core/fmtx"
+	"github.com/behavioral-ai/intermediary/config"
+	"github.com/behavioral-ai/intermediary/latency"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,8 +19,62 @@ const (
 	LogKey      = "log"
 	LogRouteKey = "route-name"
 	TimeoutKey  = "timeout"
+	IntervalKey = "interval"
 
-	defaultTimeout = time.Millisecond * 2500
+	TimeoutModeKey       = latency.TimeoutModeKey
+	TimeoutPercentileKey = latency.TimeoutPercentileKey
+	TimeoutFactorKey     = latency.TimeoutFactorKey
+	TimeoutMinKey        = latency.TimeoutMinKey
+	TimeoutMaxKey        = latency.TimeoutMaxKey
+
+	ForwardedKey         = "forwarded"
+	RequestHeaderAddKey  = "request-header-add"
+	RequestHeaderSetKey  = "request-header-set"
+	RequestHeaderRmKey   = "request-header-remove"
+	ResponseHeaderAddKey = "response-header-add"
+	ResponseHeaderSetKey = "response-header-set"
+	ResponseHeaderRmKey  = "response-header-remove"
+
+	PathStripPrefixKey = "path-strip-prefix"
+	PathAddPrefixKey   = "path-add-prefix"
+	PathPatternKey     = "path-pattern"
+	PathReplaceKey     = "path-replace"
+	QueryAddKey        = "query-add"
+	QueryRemoveKey     = "query-remove"
+
+	MirrorHostKey       = "mirror-host"
+	MirrorPercentageKey = "mirror-percentage"
+	MirrorMethodsKey    = "mirror-methods"
+
+	CanaryHostKey       = "canary-host"
+	CanaryPercentageKey = "canary-percentage"
+	CanaryHeaderKey     = "canary-header"
+	CanaryCookieKey     = "canary-cookie"
+	CanaryClientKey     = "canary-client"
+
+	FailoverHostsKey  = "failover-hosts"
+	FailoverStatusKey = "failover-status-codes"
+	HealthPathKey     = "health-path"
+	HealthIntervalKey = "health-interval"
+
+	HedgePercentileKey = "hedge-percentile"
+	HedgeBudgetKey     = "hedge-budget"
+	HedgeMinDelayKey   = "hedge-min-delay"
+
+	LimitKey          = "limit"
+	LimitInitialKey   = "limit-initial"
+	LimitMinKey       = "limit-min"
+	LimitMaxKey       = "limit-max"
+	LimitLatencyKey   = "limit-latency"
+	LimitQueueKey     = "limit-queue"
+	LimitQueueWaitKey = "limit-queue-wait"
+
+	CompressKey        = "compress"
+	CompressTypesKey   = "compress-types"
+	CompressMinSizeKey = "compress-min-size"
+
+	defaultTimeout  = time.Millisecond * 2500
+	defaultInterval = time.Minute
 )
 
 type Routing struct {
@@ -22,26 +82,49 @@ type Routing struct {
 	AppHost      string // User requirement
 	LogRouteName string
 	Timeout      time.Duration
+	Interval     time.Duration   // Collective resolution interval
+	Adaptive     latency.Timeout // Adaptive timeout, Timeout is used when static
+	Forwarded    bool            // Add X-Forwarded-* and Forwarded request headers
+	Request      Header          // Request header rules
+	Response     Header          // Response header rules
+	Rewrite      Rewrite
+
+	MirrorHost       string   // Shadow host, responses are discarded
+	MirrorPercentage int      // Percentage of requests mirrored
+	MirrorMethods    []string // Methods that are mirrored, safe methods by default so side effects are not duplicated
+	Canary           Canary
+	Failover         Failover
+	Hedge            Hedge
+	Limit            Limit
+	Compress         Compress
 }
 
+var (
+	defaultMirrorMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions}
+)
+
 func Initialize(m map[string]string) *Routing {
 	r := new(Routing)
 	r.Log = true
 	r.LogRouteName = logRouteName
 	r.Timeout = defaultTimeout
+	r.Interval = defaultInterval
+	r.Adaptive = latency.NewTimeout()
+	r.Forwarded = true
+	r.MirrorMethods = defaultMirrorMethods
+	initFailover(&r.Failover)
+	initHedge(&r.Hedge)
+	initLimit(&r.Limit)
+	initCompress(&r.Compress)
 	parseRouting(r, m)
 	return r
 }
 
-/*
-func NewRouting(name string) *Routing {
-	//m, _ := resource.Resolve[map[string]string](name, Fragment, resource.Resolver)
-	return newRouting(nil)
+// Validate - check the values of a configuration map that would otherwise not be applied
+func Validate(m map[string]string) error {
+	return parseRewrite(new(Rewrite), m)
 }
 
-
-*/
-
 func newRouting(m map[string]string) *Routing {
 	c := Initialize(m)
 	return c
@@ -51,6 +134,37 @@ func (r *Routing) Update(m map[string]string) {
 	parseRouting(r, m)
 }
 
+// Map - export the configuration, including defaults, as a configuration map. Parsing the map returns the same
+// configuration.
+func (r *Routing) Map() map[string]string {
+	m := make(map[string]string)
+	m[LogKey] = config.FormatBool(r.Log)
+	m[LogRouteKey] = r.LogRouteName
+	m[ForwardedKey] = config.FormatBool(r.Forwarded)
+	if r.AppHost != "" {
+		m[AppHostKey] = r.AppHost
+	}
+	m[TimeoutKey] = config.FormatDuration(r.Timeout)
+	m[IntervalKey] = config.FormatDuration(r.Interval)
+	if r.MirrorHost != "" {
+		m[MirrorHostKey] = r.MirrorHost
+	}
+	m[MirrorPercentageKey] = strconv.Itoa(r.MirrorPercentage)
+	if len(r.MirrorMethods) > 0 {
+		m[MirrorMethodsKey] = config.FormatList(r.MirrorMethods)
+	}
+	formatHeader(r.Request, m, RequestHeaderAddKey, RequestHeaderSetKey, RequestHeaderRmKey)
+	formatHeader(r.Response, m, ResponseHeaderAddKey, ResponseHeaderSetKey, ResponseHeaderRmKey)
+	formatRewrite(r.Rewrite, m)
+	formatCanary(r.Canary, m)
+	formatFailover(r.Failover, m)
+	formatHedge(r.Hedge, m)
+	formatLimit(r.Limit, m)
+	formatCompress(r.Compress, m)
+	latency.FormatTimeout(r.Adaptive, m)
+	return m
+}
+
 func parseRouting(r *Routing, m map[string]string) {
 	if r == nil || m == nil {
 		return
@@ -67,6 +181,19 @@ func parseRouting(r *Routing, m map[string]string) {
 	if s != "" {
 		r.LogRouteName = s
 	}
+	s = m[ForwardedKey]
+	if s != "" {
+		r.Forwarded = s == "true"
+	}
+	parseHeader(&r.Request, m[RequestHeaderAddKey], m[RequestHeaderSetKey], m[RequestHeaderRmKey])
+	parseHeader(&r.Response, m[ResponseHeaderAddKey], m[ResponseHeaderSetKey], m[ResponseHeaderRmKey])
+	parseRewrite(&r.Rewrite, m)
+	parseCanary(&r.Canary, m)
+	parseFailover(&r.Failover, m)
+	parseHedge(&r.Hedge, m)
+	parseLimit(&r.Limit, m)
+	parseCompress(&r.Compress, m)
+	latency.ParseTimeout(&r.Adaptive, m)
 	s = m[AppHostKey]
 	if s != "" {
 		r.AppHost = s
@@ -80,4 +207,45 @@ func parseRouting(r *Routing, m map[string]string) {
 		}
 		r.Timeout = dur
 	}
+	s = m[IntervalKey]
+	if s != "" {
+		dur, err := fmtx.ParseDuration(s)
+		if err != nil {
+			return
+		}
+		r.Interval = dur
+	}
+	s = m[MirrorHostKey]
+	if s != "" {
+		r.MirrorHost = s
+	}
+	s = m[MirrorPercentageKey]
+	if s != "" {
+		pct, err := parsePercentage(s)
+		if err != nil {
+			return
+		}
+		r.MirrorPercentage = pct
+	}
+	s = m[MirrorMethodsKey]
+	if s != "" {
+		r.MirrorMethods = nil
+		for _, method := range parseList(s) {
+			r.MirrorMethods = append(r.MirrorMethods, strings.ToUpper(method))
+		}
+	}
+}
+
+// Mirrored - determine if requests with the method are mirrored
+func (r *Routing) Mirrored(method string) bool {
+	return slices.Contains(r.MirrorMethods, method)
+}
+
+// parsePercentage - parse an integer percentage, bounded to 0-100
+func parsePercentage(s string) (int, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	return min(max(i, 0), 100), nil
 }
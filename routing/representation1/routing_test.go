@@ -2,7 +2,8 @@ package representation1
 
 import (
 	"fmt"
-	"github.com/behavioral-ai/collective/resource"
+	"maps"
+	"reflect"
 )
 
 const (
@@ -25,30 +26,42 @@ func ExampleParseRouting() {
 	fmt.Printf("test: parseRouting() -> %v\n", routing)
 
 	//Output:
-	//test: parseRouting() -> {true www.google.com app2 750ms}
+	//test: parseRouting() -> {true www.google.com app2 750ms 0s { 0 0 0s 0s} false {map[] map[] []} {map[] map[] []} {  <nil>  map[] []}  0 [] { 0     } {[] []  0s} {0 0 0s} { 0 0 0 0s 0 0s} {false [] 0}}
 
 }
 
-func _ExampleNewRouting() {
-	resource.NewAgent()
+func ExampleRouting_Map() {
+	r := Initialize(map[string]string{
+		AppHostKey:           "localhost:8080",
+		TimeoutKey:           "1500ms",
+		ForwardedKey:         "false",
+		RequestHeaderSetKey:  "X-Tenant:orders|Accept:application/json",
+		ResponseHeaderRmKey:  "server|x-powered-by",
+		PathPatternKey:       "^/v1/(.*)$",
+		PathReplaceKey:       "/v2/${1}",
+		QueryAddKey:          "region=us|debug=false",
+		CanaryHostKey:        "localhost:8082",
+		CanaryHeaderKey:      "X-Canary:true",
+		CanaryPercentageKey:  "5",
+		FailoverHostsKey:     "localhost:8083|localhost:8084",
+		HedgePercentileKey:   "95",
+		LimitKey:             LimitAIMD,
+		LimitQueueWaitKey:    "250ms",
+		CompressKey:          "true",
+		TimeoutPercentileKey: "99.9",
+	})
+	m2 := r.Map()
+	fmt.Printf("test: Map() -> [timeout:%v] [request-header-set:%v] [response-header-remove:%v] [query-add:%v]\n",
+		m2[TimeoutKey], m2[RequestHeaderSetKey], m2[ResponseHeaderRmKey], m2[QueryAddKey])
+	fmt.Printf("test: Map() -> [canary-header:%v] [failover-status-codes:%v] [health-interval:%v] [limit-queue-wait:%v] [timeout-percentile:%v]\n",
+		m2[CanaryHeaderKey], m2[FailoverStatusKey], m2[HealthIntervalKey], m2[LimitQueueWaitKey], m2[TimeoutPercentileKey])
 
-	status := resource.Resolver.AddRepresentation(NamespaceName, Fragment, "author", m)
-	fmt.Printf("test: AddRepresentation() -> [status:%v]\n", status)
-
-	ct, status2 := resource.Resolver.Representation(NamespaceName)
-	fmt.Printf("test: Representation() -> [ct:%v] [status:%v]\n", ct, status2)
-
-	if buf, ok := ct.Value.([]byte); ok {
-		fmt.Printf("test: Representation() -> [value:%v] [status:%v]\n", len(buf), status2)
-	}
-
-	//l := NewRouting(NamespaceName)
-	//fmt.Printf("test: NewRouting() -> %v\n", l)
+	r2 := Initialize(m2)
+	fmt.Printf("test: Initialize(Map()) -> [equal:%v] [deep-equal:%v]\n", maps.Equal(m2, r2.Map()), reflect.DeepEqual(r, r2))
 
 	//Output:
-	//test: AddRepresentation() -> [status:OK]
-	//test: Representation() -> [ct:fragment: v1 type: application/json value: true] [status:OK]
-	//test: Representation() -> [value:80] [status:OK]
-	//test: NewRouting() -> &{true www.google.com app2 750ms}
+	//test: Map() -> [timeout:1500ms] [request-header-set:Accept:application/json|X-Tenant:orders] [response-header-remove:Server|X-Powered-By] [query-add:debug=false|region=us]
+	//test: Map() -> [canary-header:X-Canary:true] [failover-status-codes:502|503|504] [health-interval:10s] [limit-queue-wait:250ms] [timeout-percentile:99.9]
+	//test: Initialize(Map()) -> [equal:true] [deep-equal:true]
 
 }
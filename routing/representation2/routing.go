@@ -0,0 +1,87 @@
+package representation2
+
+import (
+	"github.com/behavioral-ai/intermediary/representation"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+)
+
+// Version 2 adds a route table, mapping route names to hosts, replacing the per route host keys.
+//
+//	routes : "app:www.google.com|mirror:shadow.google.com|canary:canary.google.com"
+
+const (
+	Fragment  = "v2"
+	RoutesKey = "routes"
+
+	AppRoute    = "app"
+	MirrorRoute = "mirror"
+	CanaryRoute = "canary"
+)
+
+var (
+	routes = []representation.Field{
+		{Name: AppRoute, Key: representation1.AppHostKey},
+		{Name: MirrorRoute, Key: representation1.MirrorHostKey},
+		{Name: CanaryRoute, Key: representation1.CanaryHostKey},
+	}
+
+	// Schema - routing representation versions
+	Schema = representation.Schema{
+		{Fragment: representation1.Fragment, Validate: representation1.Validate},
+		{Fragment: Fragment, Upgrade: upgrade, Validate: validate},
+	}
+)
+
+// Routing - version 2 representation, the version 1 representation configured with a route table
+type Routing struct {
+	representation1.Routing
+}
+
+// Initialize - create a representation from a configuration map of any version, an invalid map is ignored
+func Initialize(m map[string]string) *Routing {
+	r := &Routing{Routing: *representation1.Initialize(nil)}
+	if m2, err := Schema.Migrate(m); err == nil {
+		r.Update(m2)
+	}
+	return r
+}
+
+// Update - apply a version 2 configuration map
+func (r *Routing) Update(m map[string]string) {
+	v1, err := downgrade(m)
+	if err != nil {
+		return
+	}
+	r.Routing.Update(v1)
+}
+
+// Map - export the configuration, including defaults, as a version 2 configuration map
+func (r *Routing) Map() map[string]string {
+	m, _ := upgrade(r.Routing.Map())
+	m[representation.VersionKey] = Fragment
+	return m
+}
+
+// upgrade - convert a version 1 configuration map
+func upgrade(m map[string]string) (map[string]string, error) {
+	v2 := representation.Without(m)
+	representation.Group(v2, RoutesKey, routes)
+	return v2, nil
+}
+
+// downgrade - convert a version 2 configuration map to the version 1 keys of the representation
+func downgrade(m map[string]string) (map[string]string, error) {
+	v1 := representation.Without(m, representation.VersionKey)
+	if err := representation.Ungroup(v1, RoutesKey, routes); err != nil {
+		return nil, err
+	}
+	return v1, nil
+}
+
+func validate(m map[string]string) error {
+	v1, err := downgrade(m)
+	if err != nil {
+		return err
+	}
+	return representation1.Validate(v1)
+}
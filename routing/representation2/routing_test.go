@@ -0,0 +1,57 @@
+package representation2
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/intermediary/representation"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"maps"
+)
+
+func ExampleInitialize() {
+	r := Initialize(map[string]string{
+		representation.VersionKey: Fragment,
+		RoutesKey:                 "app:localhost:8080|mirror:localhost:8081",
+		representation1.LogKey:    "false",
+	})
+	fmt.Printf("test: Initialize() -> [app:%v] [mirror:%v] [log:%v]\n", r.AppHost, r.MirrorHost, r.Log)
+
+	m := r.Map()
+	fmt.Printf("test: Map() -> [version:%v] [routes:%v] [app-host:%v]\n", m[representation.VersionKey], m[RoutesKey], m[representation1.AppHostKey])
+	fmt.Printf("test: Initialize(Map()) -> [equal:%v]\n", maps.Equal(m, Initialize(m).Map()))
+
+	// A map without a version is version 1
+	r = Initialize(map[string]string{representation1.AppHostKey: "localhost:8080"})
+	fmt.Printf("test: Initialize(v1) -> [app:%v]\n", r.AppHost)
+
+	//Output:
+	//test: Initialize() -> [app:localhost:8080] [mirror:localhost:8081] [log:false]
+	//test: Map() -> [version:v2] [routes:app:localhost:8080|mirror:localhost:8081] [app-host:]
+	//test: Initialize(Map()) -> [equal:true]
+	//test: Initialize(v1) -> [app:localhost:8080]
+
+}
+
+func ExampleSchema() {
+	m := map[string]string{
+		representation1.AppHostKey:    "localhost:8080",
+		representation1.CanaryHostKey: "localhost:8082",
+	}
+	v2, err := Schema.Migrate(m)
+	fmt.Printf("test: Migrate() -> [version:%v] [routes:%v] [err:%v]\n", v2[representation.VersionKey], v2[RoutesKey], err)
+
+	v1, err := downgrade(v2)
+	fmt.Printf("test: downgrade() -> [equal:%v] [err:%v]\n", maps.Equal(m, v1), err)
+
+	_, err = Schema.Migrate(map[string]string{representation.VersionKey: Fragment, RoutesKey: "primary:localhost:8080"})
+	fmt.Printf("test: Migrate() -> [err:%v]\n", err)
+
+	_, err = Schema.Migrate(map[string]string{representation.VersionKey: "v3"})
+	fmt.Printf("test: Migrate(\"v3\") -> [err:%v]\n", err)
+
+	//Output:
+	//test: Migrate() -> [version:v2] [routes:app:localhost:8080|canary:localhost:8082] [err:<nil>]
+	//test: downgrade() -> [equal:true] [err:<nil>]
+	//test: Migrate() -> [err:invalid routes [primary:localhost:8080]]
+	//test: Migrate("v3") -> [err:invalid version [v3]]
+
+}
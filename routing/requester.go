@@ -0,0 +1,91 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/core/rest"
+	"github.com/behavioral-ai/intermediary/request"
+	"io"
+	"net/http"
+	"time"
+)
+
+// requesterT - Requester for an upstream other than the default route
+type requesterT struct {
+	route   string
+	log     bool
+	timeout time.Duration
+	ex      rest.Exchange
+}
+
+func newRequester(route string, log bool, timeout time.Duration, ex rest.Exchange) *requesterT {
+	r := new(requesterT)
+	r.route = route
+	r.log = log
+	r.timeout = timeout
+	r.ex = ex
+	return r
+}
+
+// Log - implementation for Requester interface
+func (r *requesterT) Log() bool              { return r.log }
+func (r *requesterT) Route() string          { return r.route }
+func (r *requesterT) Timeout() time.Duration { return r.timeout }
+func (r *requesterT) Do() rest.Exchange      { return r.ex }
+
+// do - upstream request using the timeout for the host, and observing the host latency
+func (a *agentT) do(ctx context.Context, route, host string, ex rest.Exchange, r *http.Request, h http.Header, body io.ReadCloser) (*http.Response, *messaging.Status) {
+	state := a.state.Load()
+	var l *limiterT
+
+	cfg := state.Limit
+	if cfg.Enabled() {
+		l = a.limiter(host)
+		if reason, ok := l.acquire(ctx, cfg); !ok {
+			return rejectResponse(reason), messaging.NewStatus(http.StatusServiceUnavailable, fmt.Errorf("upstream [%v] rejected [%v]", host, reason))
+		}
+	}
+	start := time.Now().UTC()
+	resp, status := request.DoWithContext(ctx, newRequester(route, state.Log, a.timeout(host), ex), r.Method, upstreamURL(host, r, state.Rewrite), h, body)
+	elapsed := time.Since(start)
+	// A timed out request is not observed, its latency is the timeout, and observing it would raise the adaptive
+	// timeout after each slow period until it reaches the maximum
+	if status.Err == nil {
+		a.latency.Observe(host, elapsed)
+	}
+	if l != nil {
+		prev, curr := l.release(cfg, elapsed, ctx.Err() == nil && limitFailure(resp, status.Err))
+		a.limitReport(host, prev, curr)
+	}
+	return resp, status
+}
+
+// timeout - static or adaptive timeout for the host
+func (a *agentT) timeout(host string) time.Duration {
+	state := a.state.Load()
+	return state.Adaptive.Duration(a.latency.Histogram(host), state.Timeout)
+}
+
+// bufferBody - buffer the request body so that it can be sent to more than one upstream
+func bufferBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(buf))
+	return buf, nil
+}
+
+// replayBody - new reader for a buffered body
+func replayBody(body []byte) io.ReadCloser {
+	if body == nil {
+		return nil
+	}
+	return io.NopCloser(bytes.NewReader(body))
+}
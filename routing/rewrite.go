@@ -0,0 +1,63 @@
+package routing
+
+import (
+	"github.com/behavioral-ai/core/uri"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// upstreamURL - build an upstream URL for the host from the inbound request and rewrite rules
+func upstreamURL(host string, r *http.Request, rw representation1.Rewrite) string {
+	return uri.BuildURL(host, rewritePath(rw, r.URL.Path), rewriteQuery(rw, r.URL.Query()))
+}
+
+// rewritePath - strip prefix, regexp replace, and then add prefix
+func rewritePath(rw representation1.Rewrite, path string) string {
+	if rw.StripPrefix != "" {
+		path = stripPrefix(path, rw.StripPrefix)
+	}
+	if rw.Pattern != nil {
+		path = rw.Pattern.ReplaceAllString(path, rw.Replace)
+	}
+	if rw.AddPrefix != "" {
+		path = strings.TrimSuffix(rw.AddPrefix, "/") + path
+	}
+	return path
+}
+
+// stripPrefix - remove a prefix that ends on a path segment boundary, "/api" is stripped from "/api/orders"
+// but not from "/apiv2/orders"
+func stripPrefix(path, prefix string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" || !strings.HasPrefix(path, prefix) {
+		return path
+	}
+	rest := path[len(prefix):]
+	if rest == "" {
+		return "/"
+	}
+	if rest[0] != '/' {
+		return path
+	}
+	return rest
+}
+
+// rewriteQuery - remove and then inject query parameters, the original values are not modified
+func rewriteQuery(rw representation1.Rewrite, values url.Values) url.Values {
+	if len(rw.QueryAdd) == 0 && len(rw.QueryRemove) == 0 {
+		return values
+	}
+	q := make(url.Values)
+	for k, v := range values {
+		q[k] = append([]string(nil), v...)
+	}
+	for _, name := range rw.QueryRemove {
+		q.Del(name)
+	}
+	for k, v := range rw.QueryAdd {
+		q[k] = append([]string(nil), v...)
+	}
+	return q
+}
@@ -0,0 +1,74 @@
+package routing
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/routing/representation1"
+	"github.com/behavioral-ai/intermediary/routing/representation2"
+	"net/url"
+)
+
+func Example_rewritePath() {
+	rw := representation1.Initialize(map[string]string{
+		representation1.PathStripPrefixKey: "/api/v2",
+		representation1.PathAddPrefixKey:   "/internal/",
+	}).Rewrite
+	path := "/api/v2/orders/123"
+	fmt.Printf("test: rewritePath(\"%v\") -> %v\n", path, rewritePath(rw, path))
+
+	path = "/health"
+	fmt.Printf("test: rewritePath(\"%v\") -> %v\n", path, rewritePath(rw, path))
+
+	path = "/api/v2beta/orders"
+	fmt.Printf("test: rewritePath(\"%v\") -> %v\n", path, rewritePath(rw, path))
+
+	path = "/api/v2"
+	fmt.Printf("test: rewritePath(\"%v\") -> %v\n", path, rewritePath(rw, path))
+
+	rw = representation1.Initialize(map[string]string{
+		representation1.PathPatternKey: "^/api/v2/orders/([0-9]+)$",
+		representation1.PathReplaceKey: "/order-service/${1}/detail",
+	}).Rewrite
+	path = "/api/v2/orders/123"
+	fmt.Printf("test: rewritePath(\"%v\") -> %v\n", path, rewritePath(rw, path))
+
+	//Output:
+	//test: rewritePath("/api/v2/orders/123") -> /internal/orders/123
+	//test: rewritePath("/health") -> /internal/health
+	//test: rewritePath("/api/v2beta/orders") -> /internal/api/v2beta/orders
+	//test: rewritePath("/api/v2") -> /internal/
+	//test: rewritePath("/api/v2/orders/123") -> /order-service/123/detail
+
+}
+
+func Example_rewriteQuery() {
+	rw := representation1.Initialize(map[string]string{
+		representation1.QueryAddKey:    "region=us-west|version=2",
+		representation1.QueryRemoveKey: "debug|trace",
+	}).Rewrite
+	values := url.Values{"q": {"golang"}, "debug": {"true"}, "version": {"1"}}
+	q := rewriteQuery(rw, values)
+	fmt.Printf("test: rewriteQuery() -> %v\n", q.Encode())
+	fmt.Printf("test: rewriteQuery() -> [original:%v]\n", values.Encode())
+
+	//Output:
+	//test: rewriteQuery() -> q=golang&region=us-west&version=2
+	//test: rewriteQuery() -> [original:debug=true&q=golang&version=1]
+
+}
+
+func Example_rewriteInvalid() {
+	a := newAgent(representation2.Initialize(nil), nil, operationstest.NewService())
+	status := a.controller.Configure(messaging.NewMapMessage(map[string]string{
+		representation1.PathPatternKey: "^/api/(v[0-9]+",
+		representation1.QueryAddKey:    "region=us-west",
+	}))
+	fmt.Printf("test: Configure() -> [status:%v] [err:%v]\n", status.OK(), status.Err)
+	fmt.Printf("test: Configure() -> [pattern:%v] [query-add:%v] [versions:%v]\n", a.state.Load().Rewrite.Pattern, a.state.Load().Rewrite.QueryAdd, len(a.controller.History().Entries()))
+
+	//Output:
+	//test: Configure() -> [status:false] [err:invalid path-pattern [^/api/(v[0-9]+]]
+	//test: Configure() -> [pattern:<nil>] [query-add:map[]] [versions:1]
+
+}
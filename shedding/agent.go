@@ -0,0 +1,277 @@
+package shedding
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/collective/operations"
+	"github.com/behavioral-ai/collective/repository"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/core/rest"
+	"github.com/behavioral-ai/intermediary/config"
+	"github.com/behavioral-ai/intermediary/latency"
+	"github.com/behavioral-ai/intermediary/namespace"
+	"github.com/behavioral-ai/intermediary/probation"
+	"github.com/behavioral-ai/intermediary/representation"
+	"github.com/behavioral-ai/intermediary/shedding/representation1"
+	"github.com/behavioral-ai/intermediary/shedding/representation2"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	NamespaceName = "test:resiliency:agent/shedding/request/http"
+	Route         = "shedding"
+	XRejectReason = "X-Reject-Reason"
+	ReasonShed    = "load-shed"
+	shedTask      = "load-shed"
+
+	latencyPercentile = 90
+	latencyWindow     = 200
+	latencyWindowAge  = time.Second * 10
+	severeFactor      = 2
+)
+
+type agentT struct {
+	name     string
+	state    atomic.Pointer[representation2.Shedding]
+	running  atomic.Bool
+	service  *operations.Service
+	latency  atomic.Pointer[window]
+	inflight atomic.Int64
+	shed     [representation1.PriorityHigh + 1]atomic.Int64
+
+	review     *messaging.Review
+	controller *representation.Controller
+	ticker     *messaging.Ticker
+	emissary   *messaging.Channel
+}
+
+// init - register an agent constructor
+func init() {
+	repository.RegisterConstructor(NamespaceName, Constructor)
+}
+
+// Constructor - create an agent with the default representation
+func Constructor() messaging.Agent {
+	return newAgent(representation2.Initialize(nil), operations.Serve)
+}
+
+// NewInstance - create a named instance with the default representation
+func NewInstance(instance string) messaging.Agent {
+	a := newAgent(representation2.Initialize(nil), operations.Serve)
+	a.name = namespace.Instance(NamespaceName, instance)
+	return a
+}
+
+func ConstructorOverride(m map[string]string, service *operations.Service) {
+	repository.RegisterConstructor(NamespaceName, func() messaging.Agent {
+		return newAgent(representation2.Initialize(m), service)
+	})
+}
+
+// InstanceOverride - register a constructor for a named instance, each instance has its own representation
+func InstanceOverride(instance string, m map[string]string, service *operations.Service) {
+	name := namespace.Instance(NamespaceName, instance)
+	repository.RegisterConstructor(name, func() messaging.Agent {
+		a := newAgent(representation2.Initialize(m), service)
+		a.name = name
+		return a
+	})
+}
+
+func newAgent(state *representation2.Shedding, service *operations.Service) *agentT {
+	a := new(agentT)
+	a.name = NamespaceName
+	a.state.Store(state)
+	a.service = service
+	a.latency.Store(newWindow())
+	a.controller = representation.NewController(a, representation2.Schema, service)
+	a.ticker = messaging.NewTicker(messaging.ChannelEmissary, a.state.Load().Interval)
+	a.emissary = messaging.NewEmissaryChannel()
+	return a
+}
+
+// String - identity
+func (a *agentT) String() string { return a.Name() }
+
+// Name - agent identifier
+func (a *agentT) Name() string { return a.name }
+
+// Message - message the agent
+func (a *agentT) Message(m *messaging.Message) {
+	if m == nil {
+		return
+	}
+	if !a.running.Load() {
+		if m.Name == messaging.ConfigEvent {
+			a.configure(m)
+			return
+		}
+		if m.Name == config.QueryEvent {
+			config.Reply(m, a.Map())
+			return
+		}
+		if m.Name == config.HistoryEvent {
+			config.ReplyHistory(m, a.controller.History())
+			return
+		}
+		if m.Name == config.RollbackEvent {
+			messaging.Reply(m, a.controller.Rollback(m), a.Name())
+			return
+		}
+		if m.Name == messaging.StartupEvent {
+			a.controller.Resolve(true)
+			a.run()
+			a.running.Store(true)
+			return
+		}
+		return
+	}
+	if m.Name == messaging.ShutdownEvent {
+		a.running.Store(false)
+	}
+	a.emissary.C <- m
+}
+
+// Run - run the agent
+func (a *agentT) run() {
+	go emissaryAttend(a)
+}
+
+// Link - chainable exchange
+func (a *agentT) Link(next rest.Exchange) rest.Exchange {
+	return func(r *http.Request) (resp *http.Response, err error) {
+		priority := a.state.Load().Priority(r)
+		if priority < a.overload() {
+			a.shed[priority].Add(1)
+			// Shedding under load is not a failure, and shed requests are not observed for probation
+			return shedResponse(), nil
+		}
+		a.inflight.Add(1)
+		start := time.Now().UTC()
+		resp, err = next(r)
+		elapsed := time.Since(start)
+		a.window().Observe(elapsed)
+		a.inflight.Add(-1)
+		a.controller.Observe(elapsed, probation.Failure(resp, err))
+		return
+	}
+}
+
+// overload - priority below which requests are shed, low priority requests are shed when a threshold is
+// exceeded, and normal priority requests when it is severely exceeded. High priority requests are not shed.
+func (a *agentT) overload() int {
+	state := a.state.Load()
+	level := representation1.PriorityLow
+	if threshold := int64(state.Inflight); threshold > 0 {
+		level = max(level, overloadLevel(float64(a.inflight.Load()), float64(threshold)))
+	}
+	if threshold := state.Latency; threshold > 0 {
+		if d, ok := a.window().Percentile(latencyPercentile); ok {
+			level = max(level, overloadLevel(float64(d), float64(threshold)))
+		}
+	}
+	return level
+}
+
+// window - latencies of admitted requests. A window is replaced when it is older than the window age, as
+// requests that are shed are not observed, and latency shedding would not recover without new observations.
+func (a *agentT) window() *latency.Histogram {
+	w := a.latency.Load()
+	if time.Since(w.start) < latencyWindowAge {
+		return w.histogram
+	}
+	next := newWindow()
+	if a.latency.CompareAndSwap(w, next) {
+		return next.histogram
+	}
+	return a.latency.Load().histogram
+}
+
+type window struct {
+	start     time.Time
+	histogram *latency.Histogram
+}
+
+func newWindow() *window {
+	return &window{start: time.Now().UTC(), histogram: latency.NewHistogram(latencyWindow)}
+}
+
+func overloadLevel(observed, threshold float64) int {
+	if observed > threshold*severeFactor {
+		return representation1.PriorityHigh
+	}
+	if observed > threshold {
+		return representation1.PriorityNormal
+	}
+	return representation1.PriorityLow
+}
+
+// publish - report shed counts since the last publish
+func (a *agentT) publish() {
+	low := a.shed[representation1.PriorityLow].Swap(0)
+	normal := a.shed[representation1.PriorityNormal].Swap(0)
+	if low == 0 && normal == 0 {
+		return
+	}
+	a.service.Trace(a.Name(), shedTask, fmt.Sprintf("shed [%v:%v] [%v:%v]", representation1.LowValue, low, representation1.NormalValue, normal), "")
+}
+
+func (a *agentT) trace(task, observation, action string) {
+	if a.review == nil {
+		return
+	}
+	if !a.review.Started() {
+		a.review.Start()
+	}
+	if a.review.Expired() {
+		return
+	}
+	a.service.Trace(a.Name(), task, observation, action)
+}
+
+func (a *agentT) configure(m *messaging.Message) {
+	switch m.ContentType() {
+	case messaging.ContentTypeMap:
+		if status := a.controller.Configure(m); !status.OK() {
+			messaging.Reply(m, status, a.Name())
+			return
+		}
+	case messaging.ContentTypeReview:
+		r, status := messaging.ReviewContent(m)
+		if !status.OK() {
+			messaging.Reply(m, status, a.Name())
+			return
+		}
+		a.review = r
+	}
+	messaging.Reply(m, messaging.StatusOK(), a.Name())
+}
+
+// Update - implementation for representation.Agent interface, a configuration map is applied to a copy of the
+// representation, and requests in progress keep the current representation
+func (a *agentT) Update(m map[string]string) {
+	s := representation2.Initialize(a.state.Load().Map())
+	s.Update(m)
+	a.state.Store(s)
+}
+
+// Restore - implementation for representation.Agent interface
+func (a *agentT) Restore(m map[string]string) {
+	a.state.Store(representation2.Initialize(m))
+}
+
+// Map - implementation for representation.Agent interface
+func (a *agentT) Map() map[string]string { return a.state.Load().Map() }
+
+func (a *agentT) emissaryShutdown() {
+	a.emissary.Close()
+	a.ticker.Stop()
+}
+
+func shedResponse() *http.Response {
+	h := make(http.Header)
+	h.Set(XRejectReason, ReasonShed)
+	return httpx.NewResponse(http.StatusServiceUnavailable, h, nil)
+}
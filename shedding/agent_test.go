@@ -0,0 +1,124 @@
+package shedding
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/collective/operations/operationstest"
+	"github.com/behavioral-ai/core/httpx"
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/probation"
+	"github.com/behavioral-ai/intermediary/shedding/representation1"
+	"github.com/behavioral-ai/intermediary/shedding/representation2"
+	"net/http"
+	"time"
+)
+
+func Example_newAgent() {
+	a := newAgent(representation2.Initialize(nil), operationstest.NewService())
+
+	fmt.Printf("test: newAgent() -> %v\n", a.Name())
+	m := make(map[string]string)
+	m[representation1.InflightThresholdKey] = "10"
+	a.Message(messaging.NewMapMessage(m))
+	fmt.Printf("test: Message() -> %v\n", a.state.Load().Inflight)
+
+	//Output:
+	//test: newAgent() -> test:resiliency:agent/shedding/request/http
+	//test: Message() -> 10
+
+}
+
+func okExchange(r *http.Request) (*http.Response, error) {
+	return httpx.NewResponse(http.StatusOK, nil, nil), nil
+}
+
+func Example_link() {
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.HighPathKey:          "^/checkout",
+		representation1.LowPathKey:           "^/analytics",
+		representation1.InflightThresholdKey: "10",
+	}), operationstest.NewService())
+	ex := a.Link(okExchange)
+
+	send := func(path string) string {
+		req, _ := http.NewRequest(http.MethodGet, "https://localhost:8081"+path, nil)
+		resp, _ := ex(req)
+		return fmt.Sprintf("%v%v", resp.StatusCode, resp.Header.Get(XRejectReason))
+	}
+	fmt.Printf("test: Link() -> [inflight:%v] [checkout:%v] [search:%v] [analytics:%v]\n", a.inflight.Load(), send("/checkout"), send("/search"), send("/analytics"))
+
+	a.inflight.Store(15)
+	fmt.Printf("test: Link() -> [inflight:%v] [checkout:%v] [search:%v] [analytics:%v]\n", a.inflight.Load(), send("/checkout"), send("/search"), send("/analytics"))
+
+	a.inflight.Store(25)
+	fmt.Printf("test: Link() -> [inflight:%v] [checkout:%v] [search:%v] [analytics:%v]\n", a.inflight.Load(), send("/checkout"), send("/search"), send("/analytics"))
+	fmt.Printf("test: Link() -> [shed-low:%v] [shed-normal:%v] [shed-high:%v]\n", a.shed[representation1.PriorityLow].Load(), a.shed[representation1.PriorityNormal].Load(), a.shed[representation1.PriorityHigh].Load())
+
+	//Output:
+	//test: Link() -> [inflight:0] [checkout:200] [search:200] [analytics:200]
+	//test: Link() -> [inflight:15] [checkout:200] [search:200] [analytics:503load-shed]
+	//test: Link() -> [inflight:25] [checkout:200] [search:503load-shed] [analytics:503load-shed]
+	//test: Link() -> [shed-low:2] [shed-normal:1] [shed-high:0]
+
+}
+
+func Example_linkRecovery() {
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.HighPathKey:         "^/checkout",
+		representation1.LatencyThresholdKey: "100ms",
+	}), operationstest.NewService())
+	ex := a.Link(okExchange)
+
+	send := func(path string) string {
+		req, _ := http.NewRequest(http.MethodGet, "https://localhost:8081"+path, nil)
+		resp, _ := ex(req)
+		return fmt.Sprintf("%v%v", resp.StatusCode, resp.Header.Get(XRejectReason))
+	}
+	for i := 0; i < 50; i++ {
+		a.window().Observe(time.Millisecond * 500)
+	}
+	fmt.Printf("test: Link() -> [overload:%v] [checkout:%v] [search:%v]\n", a.overload(), send("/checkout"), send("/search"))
+
+	// Shed requests are not observed, so latency shedding recovers when the window expires
+	a.latency.Load().start = time.Now().UTC().Add(-latencyWindowAge)
+	fmt.Printf("test: Link() -> [overload:%v] [checkout:%v] [search:%v]\n", a.overload(), send("/checkout"), send("/search"))
+
+	//Output:
+	//test: Link() -> [overload:2] [checkout:200] [search:503load-shed]
+	//test: Link() -> [overload:0] [checkout:200] [search:200]
+
+}
+
+func Example_linkProbation() {
+	a := newAgent(representation2.Initialize(map[string]string{
+		representation1.LowPathKey:           "^/analytics",
+		representation1.InflightThresholdKey: "10",
+	}), operationstest.NewService())
+	ex := a.Link(okExchange)
+
+	send := func(path string, n int) {
+		for i := 0; i < n; i++ {
+			req, _ := http.NewRequest(http.MethodGet, "https://localhost:8081"+path, nil)
+			ex(req)
+		}
+	}
+	send("/search", 50)
+
+	a.Message(messaging.NewMapMessage(map[string]string{
+		representation1.InflightThresholdKey: "5",
+		probation.GuardKey:                   "5m",
+	}))
+	fmt.Printf("test: Message() -> [inflight-threshold:%v] [active:%v]\n", a.state.Load().Inflight, a.controller.Monitor().Active())
+
+	// Shed requests are not failures, so the change is not reverted
+	a.inflight.Store(8)
+	send("/analytics", 30)
+	time.Sleep(time.Millisecond * 300)
+	send("/search", 20)
+	fmt.Printf("test: Link() -> [inflight-threshold:%v] [active:%v] [shed-low:%v] [versions:%v]\n", a.state.Load().Inflight, a.controller.Monitor().Active(),
+		a.shed[representation1.PriorityLow].Load(), len(a.controller.History().Entries()))
+
+	//Output:
+	//test: Message() -> [inflight-threshold:5] [active:true]
+	//test: Link() -> [inflight-threshold:5] [active:true] [shed-low:30] [versions:2]
+
+}
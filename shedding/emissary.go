@@ -0,0 +1,45 @@
+package shedding
+
+import (
+	"github.com/behavioral-ai/core/messaging"
+	"github.com/behavioral-ai/intermediary/config"
+)
+
+// emissary attention
+func emissaryAttend(a *agentT) {
+	paused := false
+
+	for {
+		select {
+		case <-a.ticker.C():
+			if !paused {
+				a.controller.Resolve(false)
+				a.publish()
+			}
+		default:
+		}
+		select {
+		case msg := <-a.emissary.C:
+			switch msg.Name {
+			case messaging.PauseEvent:
+				paused = true
+			case messaging.ResumeEvent:
+				paused = false
+			case messaging.ConfigEvent:
+				a.configure(msg)
+			case config.QueryEvent:
+				config.Reply(msg, a.Map())
+			case config.HistoryEvent:
+				config.ReplyHistory(msg, a.controller.History())
+			case config.RollbackEvent:
+				messaging.Reply(msg, a.controller.Rollback(msg), a.Name())
+			case messaging.ShutdownEvent:
+				a.publish()
+				a.emissaryShutdown()
+				return
+			default:
+			}
+		default:
+		}
+	}
+}
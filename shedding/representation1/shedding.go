@@ -0,0 +1,171 @@
+package representation1
+
+import (
+	"github.com/behavioral-ai/core/fmtx"
+	"github.com/behavioral-ai/intermediary/config"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Fragment             = "v1"
+	PriorityHeaderKey    = "priority-header"
+	HighPathKey          = "high-path"
+	LowPathKey           = "low-path"
+	LowMethodsKey        = "low-methods"
+	LatencyThresholdKey  = "latency-threshold"
+	InflightThresholdKey = "inflight-threshold"
+	IntervalKey          = "interval"
+	listSeparator        = "|"
+
+	PriorityLow    = 0
+	PriorityNormal = 1
+	PriorityHigh   = 2
+
+	LowValue    = "low"
+	NormalValue = "normal"
+	HighValue   = "high"
+
+	defaultInterval = time.Minute
+)
+
+type Shedding struct {
+	Interval       time.Duration
+	PriorityHeader string         // Header with a low, normal, or high priority value, set by a trusted proxy, empty to disable
+	HighPath       *regexp.Regexp // User requirement
+	LowPath        *regexp.Regexp // User requirement
+	LowMethods     []string       // User requirement
+	Latency        time.Duration  // Latency threshold, zero to disable
+	Inflight       int            // In-flight request threshold, zero to disable
+}
+
+// Initialize - add a default policy
+func Initialize(m map[string]string) *Shedding {
+	s := new(Shedding)
+	s.Interval = defaultInterval
+	parseShedding(s, m)
+	return s
+}
+
+func (s *Shedding) Update(m map[string]string) {
+	parseShedding(s, m)
+}
+
+// ParsePriority - priority from a header value, false if the value is not a priority
+func ParsePriority(s string) (int, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case LowValue:
+		return PriorityLow, true
+	case NormalValue:
+		return PriorityNormal, true
+	case HighValue:
+		return PriorityHigh, true
+	}
+	return PriorityNormal, false
+}
+
+// Map - export the configuration, including defaults, as a configuration map. Parsing the map returns the same
+// configuration, runtime state is not included.
+func (s *Shedding) Map() map[string]string {
+	m := make(map[string]string)
+	if s.PriorityHeader != "" {
+		m[PriorityHeaderKey] = s.PriorityHeader
+	}
+	if s.HighPath != nil {
+		m[HighPathKey] = s.HighPath.String()
+	}
+	if s.LowPath != nil {
+		m[LowPathKey] = s.LowPath.String()
+	}
+	if len(s.LowMethods) > 0 {
+		m[LowMethodsKey] = config.FormatList(s.LowMethods)
+	}
+	m[LatencyThresholdKey] = config.FormatDuration(s.Latency)
+	m[InflightThresholdKey] = strconv.Itoa(s.Inflight)
+	m[IntervalKey] = config.FormatDuration(s.Interval)
+	return m
+}
+
+func parseShedding(c *Shedding, m map[string]string) {
+	if c == nil || m == nil {
+		return
+	}
+	s := m[PriorityHeaderKey]
+	if s != "" {
+		c.PriorityHeader = s
+	}
+	s = m[LowMethodsKey]
+	if s != "" {
+		c.LowMethods = nil
+		for _, method := range strings.Split(s, listSeparator) {
+			if method = strings.TrimSpace(method); method != "" {
+				c.LowMethods = append(c.LowMethods, strings.ToUpper(method))
+			}
+		}
+	}
+	s = m[HighPathKey]
+	if s != "" {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return
+		}
+		c.HighPath = re
+	}
+	s = m[LowPathKey]
+	if s != "" {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return
+		}
+		c.LowPath = re
+	}
+	s = m[InflightThresholdKey]
+	if s != "" {
+		i, err := strconv.Atoi(s)
+		if err != nil || i < 0 {
+			return
+		}
+		c.Inflight = i
+	}
+	s = m[LatencyThresholdKey]
+	if s != "" {
+		dur, err := fmtx.ParseDuration(s)
+		if err != nil {
+			return
+		}
+		c.Latency = dur
+	}
+	s = m[IntervalKey]
+	if s != "" {
+		dur, err := fmtx.ParseDuration(s)
+		if err != nil {
+			return
+		}
+		c.Interval = dur
+	}
+}
+
+// Priority - classify a request by header, then path, then method. The header is only used when configured,
+// as a client could otherwise send a high priority.
+func (s *Shedding) Priority(r *http.Request) int {
+	if s.PriorityHeader != "" {
+		if p, ok := ParsePriority(r.Header.Get(s.PriorityHeader)); ok {
+			return p
+		}
+	}
+	if s.HighPath != nil && s.HighPath.MatchString(r.URL.Path) {
+		return PriorityHigh
+	}
+	if s.LowPath != nil && s.LowPath.MatchString(r.URL.Path) {
+		return PriorityLow
+	}
+	for _, method := range s.LowMethods {
+		if r.Method == method {
+			return PriorityLow
+		}
+	}
+	return PriorityNormal
+}
@@ -0,0 +1,76 @@
+package representation1
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+)
+
+var (
+	m = map[string]string{
+		PriorityHeaderKey:    "X-Traffic-Priority",
+		HighPathKey:          "^/checkout",
+		LowPathKey:           "^/analytics",
+		LowMethodsKey:        "options|head",
+		LatencyThresholdKey:  "500ms",
+		InflightThresholdKey: "100",
+		IntervalKey:          "30s",
+	}
+)
+
+func ExampleInitialize() {
+	var shed Shedding
+	parseShedding(&shed, m)
+
+	fmt.Printf("test: parseShedding() -> [header:%v] [high:%v] [low:%v] [methods:%v] [latency:%v] [inflight:%v] [interval:%v]\n",
+		shed.PriorityHeader, shed.HighPath, shed.LowPath, shed.LowMethods, shed.Latency, shed.Inflight, shed.Interval)
+
+	//Output:
+	//test: parseShedding() -> [header:X-Traffic-Priority] [high:^/checkout] [low:^/analytics] [methods:[OPTIONS HEAD]] [latency:500ms] [inflight:100] [interval:30s]
+
+}
+
+func ExampleShedding_Priority() {
+	s := Initialize(m)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://localhost:8081/checkout/cart", nil)
+	fmt.Printf("test: Priority(\"%v %v\") -> %v\n", req.Method, req.URL.Path, s.Priority(req))
+
+	req, _ = http.NewRequest(http.MethodPost, "https://localhost:8081/analytics/event", nil)
+	fmt.Printf("test: Priority(\"%v %v\") -> %v\n", req.Method, req.URL.Path, s.Priority(req))
+
+	req.Header.Add("X-Traffic-Priority", "high")
+	fmt.Printf("test: Priority(\"%v %v\") -> [header:high] %v\n", req.Method, req.URL.Path, s.Priority(req))
+
+	// Priority header is not used by default
+	req.Header.Set("X-Priority", "high")
+	fmt.Printf("test: Priority(\"%v %v\") -> [default] [header:high] %v\n", req.Method, req.URL.Path, Initialize(nil).Priority(req))
+
+	req, _ = http.NewRequest(http.MethodHead, "https://localhost:8081/search", nil)
+	fmt.Printf("test: Priority(\"%v %v\") -> %v\n", req.Method, req.URL.Path, s.Priority(req))
+
+	req, _ = http.NewRequest(http.MethodGet, "https://localhost:8081/search", nil)
+	fmt.Printf("test: Priority(\"%v %v\") -> %v\n", req.Method, req.URL.Path, s.Priority(req))
+
+	//Output:
+	//test: Priority("POST /checkout/cart") -> 2
+	//test: Priority("POST /analytics/event") -> 0
+	//test: Priority("POST /analytics/event") -> [header:high] 2
+	//test: Priority("POST /analytics/event") -> [default] [header:high] 1
+	//test: Priority("HEAD /search") -> 0
+	//test: Priority("GET /search") -> 1
+
+}
+
+func ExampleShedding_Map() {
+	s := Initialize(m)
+	m2 := s.Map()
+	fmt.Printf("test: Map() -> %v\n", m2)
+
+	fmt.Printf("test: Initialize(Map()) -> [equal:%v]\n", maps.Equal(m2, Initialize(m2).Map()))
+
+	//Output:
+	//test: Map() -> map[high-path:^/checkout inflight-threshold:100 interval:30s latency-threshold:500ms low-methods:OPTIONS|HEAD low-path:^/analytics priority-header:X-Traffic-Priority]
+	//test: Initialize(Map()) -> [equal:true]
+
+}
@@ -0,0 +1,82 @@
+package representation2
+
+import (
+	"github.com/behavioral-ai/intermediary/representation"
+	"github.com/behavioral-ai/intermediary/shedding/representation1"
+)
+
+// Version 2 groups the overload thresholds into a single key.
+//
+//	thresholds : "latency:500ms|inflight:100"
+
+const (
+	Fragment      = "v2"
+	ThresholdsKey = "thresholds"
+
+	LatencyThreshold  = "latency"
+	InflightThreshold = "inflight"
+)
+
+var (
+	thresholds = []representation.Field{
+		{Name: LatencyThreshold, Key: representation1.LatencyThresholdKey},
+		{Name: InflightThreshold, Key: representation1.InflightThresholdKey},
+	}
+
+	// Schema - shedding representation versions
+	Schema = representation.Schema{
+		{Fragment: representation1.Fragment},
+		{Fragment: Fragment, Upgrade: upgrade, Validate: validate},
+	}
+)
+
+// Shedding - version 2 representation, the version 1 representation configured with grouped thresholds
+type Shedding struct {
+	representation1.Shedding
+}
+
+// Initialize - create a representation from a configuration map of any version, an invalid map is ignored
+func Initialize(m map[string]string) *Shedding {
+	s := &Shedding{Shedding: *representation1.Initialize(nil)}
+	if m2, err := Schema.Migrate(m); err == nil {
+		s.Update(m2)
+	}
+	return s
+}
+
+// Update - apply a version 2 configuration map
+func (s *Shedding) Update(m map[string]string) {
+	v1, err := downgrade(m)
+	if err != nil {
+		return
+	}
+	s.Shedding.Update(v1)
+}
+
+// Map - export the configuration, including defaults, as a version 2 configuration map
+func (s *Shedding) Map() map[string]string {
+	m, _ := upgrade(s.Shedding.Map())
+	m[representation.VersionKey] = Fragment
+	return m
+}
+
+// upgrade - convert a version 1 configuration map
+func upgrade(m map[string]string) (map[string]string, error) {
+	v2 := representation.Without(m)
+	representation.Group(v2, ThresholdsKey, thresholds)
+	return v2, nil
+}
+
+// downgrade - convert a version 2 configuration map to the version 1 keys of the representation
+func downgrade(m map[string]string) (map[string]string, error) {
+	v1 := representation.Without(m, representation.VersionKey)
+	if err := representation.Ungroup(v1, ThresholdsKey, thresholds); err != nil {
+		return nil, err
+	}
+	return v1, nil
+}
+
+func validate(m map[string]string) error {
+	_, err := downgrade(m)
+	return err
+}
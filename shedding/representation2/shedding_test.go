@@ -0,0 +1,36 @@
+package representation2
+
+import (
+	"fmt"
+	"github.com/behavioral-ai/intermediary/representation"
+	"github.com/behavioral-ai/intermediary/shedding/representation1"
+	"maps"
+)
+
+func ExampleInitialize() {
+	s := Initialize(map[string]string{
+		representation.VersionKey:  Fragment,
+		ThresholdsKey:              "latency:500ms|inflight:100",
+		representation1.LowPathKey: "^/analytics",
+	})
+	fmt.Printf("test: Initialize() -> [latency:%v] [inflight:%v] [low-path:%v]\n", s.Latency, s.Inflight, s.LowPath)
+
+	m := s.Map()
+	fmt.Printf("test: Map() -> [version:%v] [thresholds:%v] [inflight-threshold:%v]\n", m[representation.VersionKey], m[ThresholdsKey], m[representation1.InflightThresholdKey])
+	fmt.Printf("test: Initialize(Map()) -> [equal:%v]\n", maps.Equal(m, Initialize(m).Map()))
+
+	// A map without a version is version 1
+	s = Initialize(map[string]string{representation1.InflightThresholdKey: "10"})
+	fmt.Printf("test: Initialize(v1) -> [inflight:%v]\n", s.Inflight)
+
+	_, err := Schema.Migrate(map[string]string{representation.VersionKey: Fragment, ThresholdsKey: "cpu:80"})
+	fmt.Printf("test: Migrate() -> [err:%v]\n", err)
+
+	//Output:
+	//test: Initialize() -> [latency:500ms] [inflight:100] [low-path:^/analytics]
+	//test: Map() -> [version:v2] [thresholds:latency:500ms|inflight:100] [inflight-threshold:]
+	//test: Initialize(Map()) -> [equal:true]
+	//test: Initialize(v1) -> [inflight:10]
+	//test: Migrate() -> [err:invalid thresholds [cpu:80]]
+
+}